@@ -0,0 +1,175 @@
+package timerstore
+
+import (
+	"sync"
+	"time"
+)
+
+// Ack is an expired event delivered on the channel returned by Expired. Exactly
+// one of Ack and Nack takes effect; later calls, and calls after the
+// acknowledgement timed out, do nothing.
+type Ack[ID comparable, E Event] struct {
+	ID    ID
+	Event E
+
+	dl *delivery[ID, E]
+}
+
+// Ack settles the delivery as handled. With Persistent, it deletes the event
+// from the DB.
+func (a Ack[ID, E]) Ack() { a.dl.settle(true) }
+
+// Nack settles the delivery as failed. The event is stored again and
+// delivered anew after a backoff delay (see WithRedeliveryBackoff).
+func (a Ack[ID, E]) Nack() { a.dl.settle(false) }
+
+// delivery is the state of one delivered Ack.
+type delivery[ID comparable, E Event] struct {
+	s       *Simple[ID, E]
+	q       *ackQueue[ID, E]
+	id      ID
+	event   E
+	attempt int
+	onAck   func()
+	unhook  bool
+
+	mu      sync.Mutex
+	settled bool
+	timer   *time.Timer
+}
+
+func (dl *delivery[ID, E]) settle(ok bool) {
+	dl.mu.Lock()
+	if dl.settled {
+		dl.mu.Unlock()
+		return
+	}
+	dl.settled = true
+	if dl.timer != nil {
+		dl.timer.Stop()
+	}
+	dl.mu.Unlock()
+
+	<-dl.q.slots
+
+	if ok {
+		if dl.onAck != nil {
+			dl.onAck()
+		}
+		return
+	}
+
+	// A closed store drops the event; Persistent keeps it in the DB for a
+	// later Recover.
+	_ = dl.s.enqueue(dl.id, dl.event, time.Now().Add(dl.s.opts.redeliveryDelay(dl.attempt)), dl.attempt+1, dl.onAck, dl.unhook)
+}
+
+// ackQueue is the channel of Ack deliveries and the slots bounding how many
+// of them may be unsettled.
+type ackQueue[ID comparable, E Event] struct {
+	ch    chan Ack[ID, E]
+	slots chan struct{}
+	stop  chan struct{}
+}
+
+// ackQueue returns the delivery queue of the store, creating it on first use.
+func (s *Simple[ID, E]) ackQueue() *ackQueue[ID, E] {
+	s.acksOnce.Do(func() {
+		n := s.opts.maxUnacked
+		if n <= 0 {
+			n = defaultMaxUnacked
+		}
+
+		s.acks = &ackQueue[ID, E]{
+			ch:    make(chan Ack[ID, E]),
+			slots: make(chan struct{}, n),
+			stop:  make(chan struct{}),
+		}
+	})
+
+	return s.acks
+}
+
+// Expired returns the channel on which events started with Enqueue are
+// delivered when they expire. Each delivery must be settled with Ack or Nack.
+//
+// At most the configured number of deliveries may be unsettled at once (see
+// WithAckDelivery). Once that many are outstanding, or while nobody receives
+// from the channel, expiring events wait in their timer goroutines, so a
+// consumer that stops receiving holds one goroutine per expired event. A
+// delivery left unsettled for longer than the timeout is rejected as if Nack
+// was called and redelivered after the backoff delay. Close stops pending
+// deliveries; the waiting events are dropped.
+func (s *Simple[ID, E]) Expired() <-chan Ack[ID, E] { return s.ackQueue().ch }
+
+// Enqueue stores the event for pull-based delivery: when it expires, it is
+// removed from the store and delivered on the channel returned by Expired
+// instead of calling a callback.
+func (s *Simple[ID, E]) Enqueue(id ID, event E) error {
+	return s.enqueue(id, event, event.ExpireAt(), 0, nil, false)
+}
+
+func (s *Simple[ID, E]) enqueue(id ID, event E, deadline time.Time, attempt int, onAck func(), unhook bool) error {
+	dl := &delivery[ID, E]{s: s, q: s.ackQueue(), id: id, event: event, attempt: attempt, onAck: onAck, unhook: unhook}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.add(id, event, deadline, dl.deliver)
+	if err != nil {
+		return kept(err)
+	}
+	d.unhook = unhook
+
+	return nil
+}
+
+// deliver sends the delivery on the queue once a slot is free.
+func (dl *delivery[ID, E]) deliver() {
+	select {
+	case dl.q.slots <- struct{}{}:
+	case <-dl.q.stop:
+		return
+	}
+
+	timeout := dl.s.opts.ackTimeout
+	if timeout <= 0 {
+		timeout = defaultAckTimeout
+	}
+
+	select {
+	case dl.q.ch <- Ack[ID, E]{ID: dl.id, Event: dl.event, dl: dl}:
+		dl.mu.Lock()
+		if !dl.settled {
+			dl.timer = time.AfterFunc(timeout, func() { dl.settle(false) })
+		}
+		dl.mu.Unlock()
+	case <-dl.q.stop:
+		<-dl.q.slots
+	}
+}
+
+// stopAcks unblocks deliveries waiting for a slot or a receiver.
+func (s *Simple[ID, E]) stopAcks() {
+	s.acksOnce.Do(func() {})
+	if s.acks != nil {
+		close(s.acks.stop)
+	}
+}
+
+// Enqueue stores the event in the DB and in memory for pull-based delivery,
+// as Simple.Enqueue does. The event stays in the DB until its delivery is
+// acknowledged with Ack.
+func (p *Persistent[ID, E]) Enqueue(id ID, event E) error {
+	if err := p.put(id, event); err != nil {
+		return kept(err)
+	}
+
+	return p.rollback(id, event, p.s.enqueue(id, event, event.ExpireAt(), 0, func() {
+		p.delete(id, event, ReasonExpired)
+	}, true))
+}
+
+// Expired returns the channel on which events started with Enqueue are
+// delivered. See Simple.Expired.
+func (p *Persistent[ID, E]) Expired() <-chan Ack[ID, E] { return p.s.Expired() }
@@ -0,0 +1,179 @@
+package timerstore
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrExists is returned by Start for an id that is already present when the
+// replace mode is ReplaceReject.
+var ErrExists = errors.New("timerstore: id already exists")
+
+// errKeep is returned by admit when the replace mode keeps the stored event
+// instead of the incoming one. Start reports it as success.
+var errKeep = errors.New("timerstore: keep stored event")
+
+// ErrTTLOutOfBounds is returned by Start for an event due outside the bounds
+// set with WithTTLBounds in TTLReject mode.
+var ErrTTLOutOfBounds = errors.New("timerstore: TTL out of bounds")
+
+// TTLMode selects what Start does with an event due outside the bounds set
+// with WithTTLBounds.
+type TTLMode int
+
+const (
+	// TTLClamp moves the deadline to the nearest bound.
+	TTLClamp TTLMode = iota
+	// TTLReject fails with ErrTTLOutOfBounds.
+	TTLReject
+)
+
+// ReplaceMode selects what Start does for an id that is already present.
+type ReplaceMode int
+
+const (
+	// ReplaceAlways replaces the stored event with the incoming one.
+	ReplaceAlways ReplaceMode = iota
+	// ReplaceKeepEarliest replaces the stored event only if the incoming
+	// one is due sooner, so the most urgent deadline wins.
+	ReplaceKeepEarliest
+	// ReplaceKeepLatest replaces the stored event only if the incoming one
+	// is due later.
+	ReplaceKeepLatest
+	// ReplaceReject keeps the stored event and fails with ErrExists.
+	ReplaceReject
+)
+
+// admit reports whether the store accepts event for id, due at deadline. It
+// must be called with s.mu held.
+func (s *Simple[ID, E]) admit(id ID, event E, deadline time.Time) error {
+	if s.closed {
+		return ErrClosed
+	}
+
+	if s.opts.ttlMode == TTLReject {
+		if _, ok := s.bound(deadline); !ok {
+			return ErrTTLOutOfBounds
+		}
+	}
+
+	prev, exists := s.m[id]
+	if exists {
+		switch s.opts.replaceMode {
+		case ReplaceKeepEarliest:
+			if !deadline.Before(prev.deadline) {
+				return errKeep
+			}
+		case ReplaceKeepLatest:
+			if !deadline.After(prev.deadline) {
+				return errKeep
+			}
+		case ReplaceReject:
+			return ErrExists
+		}
+	}
+
+	if c := s.opts.capacity; c > 0 {
+		weight := s.weight + weightOf(event)
+		if exists {
+			weight -= prev.weight
+		}
+
+		if weight > c {
+			if s.evictor != nil && weightOf(event) <= c {
+				return errEvict
+			}
+			return ErrCapacityExceeded
+		}
+	}
+
+	return nil
+}
+
+// clampSlack is how far below the minimum TTL a deadline may fall and still
+// count as within bounds. Persistent clamps an event before putting it and
+// again when arming its timer, and without slack the second clamp would move
+// the deadline again by the time elapsed in between.
+const clampSlack = time.Millisecond
+
+// bound returns deadline brought within the TTL bounds, and whether it was
+// within them already.
+func (s *Simple[ID, E]) bound(deadline time.Time) (time.Time, bool) {
+	now := time.Now()
+	if min := s.opts.ttlMin; min > 0 && deadline.Sub(now) < min-clampSlack {
+		return now.Add(min), false
+	}
+	if max := s.opts.ttlMax; max > 0 && deadline.Sub(now) > max {
+		return now.Add(max), false
+	}
+
+	return deadline, true
+}
+
+// clamp brings deadline within the TTL bounds in TTLClamp mode, updating the
+// ExpireAt of event if it implements ExpirySetter.
+func (s *Simple[ID, E]) clamp(event E, deadline time.Time) time.Time {
+	if s.opts.ttlMode != TTLClamp {
+		return deadline
+	}
+
+	at, ok := s.bound(deadline)
+	if !ok {
+		if setter, isSetter := any(event).(ExpirySetter); isSetter {
+			setter.SetExpireAt(at)
+		}
+	}
+
+	return at
+}
+
+// check is like admit for an event due at its ExpireAt, clamped as add would
+// clamp it, but takes s.mu itself. An event the evictor may make room for
+// passes. Unlike clamp, check leaves the ExpireAt of event alone.
+func (s *Simple[ID, E]) check(id ID, event E) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deadline := event.ExpireAt()
+	if s.opts.ttlMode == TTLClamp {
+		deadline, _ = s.bound(deadline)
+	}
+	if err := s.admit(id, event, deadline); err != errEvict {
+		return err
+	}
+
+	return nil
+}
+
+// CanStart reports whether Start would accept event for id right now, without
+// changing the store. It runs the same admission checks as Start: whether the
+// store is closed, the replace mode and the capacity. When ok is false,
+// reason is the error Start would return. When Start would succeed but keep
+// the stored event under the replace mode, ok is true and reason says so;
+// otherwise reason is empty. Other goroutines may change the store before the
+// caller acts on the answer, so a later Start can still fail.
+func (s *Simple[ID, E]) CanStart(id ID, event E) (ok bool, reason string) {
+	switch err := s.check(id, event); err {
+	case nil:
+		return true, ""
+	case errKeep:
+		return true, "stored event kept by replace mode"
+	default:
+		return false, err.Error()
+	}
+}
+
+// CanStart reports whether Start would accept event for id right now, without
+// changing the store or the DB. See Simple.CanStart.
+func (p *Persistent[ID, E]) CanStart(id ID, event E) (ok bool, reason string) {
+	return p.s.CanStart(id, event)
+}
+
+// kept turns errKeep into a nil error.
+func kept(err error) error {
+	if err == errKeep {
+		return nil
+	}
+
+	return err
+}
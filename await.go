@@ -0,0 +1,109 @@
+package timerstore
+
+import "context"
+
+// Outcome tells how an event awaited with Await left the store.
+type Outcome int
+
+const (
+	// OutcomePending is returned along with ctx's error when Await gives up
+	// while the event is still stored.
+	OutcomePending Outcome = iota
+	// OutcomeNotFound is returned when no event is stored for the id.
+	OutcomeNotFound
+	// OutcomeExpired is returned for an event that expired, including one
+	// flushed by Close.
+	OutcomeExpired
+	// OutcomeCancelled is returned for an event removed by Cancel, Pop or
+	// Close, or any other removal that is not an expiry or a replacement.
+	OutcomeCancelled
+	// OutcomeReplaced is returned for an event replaced by another Start
+	// with the same id.
+	OutcomeReplaced
+)
+
+// String returns the name of the outcome.
+func (o Outcome) String() string {
+	switch o {
+	case OutcomePending:
+		return "pending"
+	case OutcomeNotFound:
+		return "not found"
+	case OutcomeExpired:
+		return "expired"
+	case OutcomeCancelled:
+		return "cancelled"
+	case OutcomeReplaced:
+		return "replaced"
+	default:
+		return "unknown"
+	}
+}
+
+// Await blocks until the event stored for id leaves the store, and returns
+// it along with how it left. An expired event is reported as soon as it is
+// removed, which may be before its callback has run or finished. If no event
+// is stored for id, Await returns OutcomeNotFound at once. If ctx is done
+// first, Await returns the event, OutcomePending and ctx.Err().
+func (s *Simple[ID, E]) Await(ctx context.Context, id ID) (E, Outcome, error) {
+	d := s.watch(id)
+	if d == nil {
+		var zeroE E
+		return zeroE, OutcomeNotFound, nil
+	}
+
+	select {
+	case <-d.done:
+		return d.event, d.outcome, nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		return d.event, OutcomePending, ctx.Err()
+	}
+}
+
+// Done returns a channel that is closed when the event stored for id leaves
+// the store, whether it expires, is cancelled or is replaced, for use in a
+// select. It is Await without the event and the outcome. If no event is
+// stored for id, the channel returned is already closed.
+func (s *Simple[ID, E]) Done(id ID) <-chan struct{} {
+	if d := s.watch(id); d != nil {
+		return d.done
+	}
+
+	return closedChan
+}
+
+var closedChan = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+// watch returns the data stored for id with its done channel made, or nil if
+// there is none.
+func (s *Simple[ID, E]) watch(id ID) *data[E] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.m[id]
+	if !ok {
+		return nil
+	}
+	if d.done == nil {
+		d.done = make(chan struct{})
+	}
+
+	return d
+}
+
+// Done returns a channel that is closed when the event stored in memory for
+// id leaves the store. See Simple.Done.
+func (p *Persistent[ID, E]) Done(id ID) <-chan struct{} { return p.s.Done(id) }
+
+// Await blocks until the event stored in memory for id leaves the store. See
+// Simple.Await.
+func (p *Persistent[ID, E]) Await(ctx context.Context, id ID) (E, Outcome, error) {
+	return p.s.Await(ctx, id)
+}
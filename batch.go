@@ -0,0 +1,148 @@
+package timerstore
+
+import (
+	"sync"
+	"time"
+)
+
+// batcher collects expired events for the batch handler until its flush
+// timer fires.
+type batcher[ID comparable, E Event] struct {
+	mu  sync.Mutex
+	buf []Entry[ID, E]
+}
+
+// SetBatchHandler registers the handler called with the events expired
+// within one flush interval of WithAfterFuncBatching, in the order they
+// expired. It only applies to events that would otherwise go to the handler
+// set with SetExpiryHandler, that is those started with a nil atExpire; it
+// takes precedence over that handler. Without batching, or with no batch
+// handler set when a batch is flushed, such events go to the expiry handler
+// one by one. Passing nil removes the handler.
+func (s *Simple[ID, E]) SetBatchHandler(handler func(batch []Entry[ID, E])) {
+	if handler == nil {
+		s.onBatch.Store(nil)
+		return
+	}
+
+	s.onBatch.Store(&handler)
+}
+
+// deduper is the key and handler set with SetDedupHandler.
+type deduper[ID comparable, E Event] struct {
+	key     func(E) string
+	handler func(id ID, event E, ids []ID)
+}
+
+// SetDedupHandler collapses the events of a batch of WithAfterFuncBatching
+// sharing the same key, as returned by key, into one call of handler, instead
+// of the batch handler, for downstreams needing one call per distinct
+// payload. Unlike Coalescer, which merges events at Start, it acts on the
+// events that expired together, whatever their deadlines were.
+//
+// Of the events sharing a key in a batch, the first to expire is the
+// representative: handler is called with its id and event, and with the ids
+// of all of them, in the order they expired, starting with id. The calls for
+// different keys are made in the order of their representatives. Passing a
+// nil key or handler removes deduplication.
+func (s *Simple[ID, E]) SetDedupHandler(key func(E) string, handler func(id ID, event E, ids []ID)) {
+	if key == nil || handler == nil {
+		s.onDedup.Store(nil)
+		return
+	}
+
+	s.onDedup.Store(&deduper[ID, E]{key: key, handler: handler})
+}
+
+// batched adds an expired event to the current batch, arming the flush timer
+// if it is the first of the batch. It reports false, adding nothing, if
+// batching is disabled or neither a batch nor a dedup handler is set.
+func (s *Simple[ID, E]) batched(id ID, event E) bool {
+	flush := s.opts.batchFlush
+	if flush <= 0 || s.onBatch.Load() == nil && s.onDedup.Load() == nil {
+		return false
+	}
+
+	b := &s.batch
+	b.mu.Lock()
+	b.buf = append(b.buf, Entry[ID, E]{ID: id, Event: event})
+	if len(b.buf) == 1 {
+		// fire runs either for an expiry counted in s.inflight or on Close
+		// before it waits, so this cannot race with a finished Wait.
+		s.inflight.Add(1)
+		time.AfterFunc(flush, s.flushBatch)
+	}
+	b.mu.Unlock()
+
+	return true
+}
+
+// flushBatch hands the current batch to the batch handler, or to the expiry
+// handler one by one if the batch handler was removed since.
+func (s *Simple[ID, E]) flushBatch() {
+	defer s.inflight.Done()
+
+	b := &s.batch
+	b.mu.Lock()
+	batch := b.buf
+	b.buf = nil
+	b.mu.Unlock()
+
+	if dd := s.onDedup.Load(); dd != nil {
+		s.dedup(batch, dd)
+		return
+	}
+
+	if h := s.onBatch.Load(); h != nil {
+		// The first event stands for the batch in the panic and timeout
+		// reports, as the representative does for a dedup group.
+		first := batch[0]
+		s.call(first.ID, first.Event, func() { (*h)(batch) })
+		return
+	}
+
+	if h := s.handler.Load(); h != nil {
+		for _, e := range batch {
+			s.call(e.ID, e.Event, func() { (*h)(e.ID, e.Event) })
+		}
+	}
+}
+
+// dedup calls the handler of dd once for every dedup key of the events in
+// batch.
+func (s *Simple[ID, E]) dedup(batch []Entry[ID, E], dd *deduper[ID, E]) {
+	type group struct {
+		rep Entry[ID, E]
+		ids []ID
+	}
+
+	var groups []*group
+	byKey := make(map[string]*group)
+	for _, e := range batch {
+		key := dd.key(e.Event)
+		g, ok := byKey[key]
+		if !ok {
+			g = &group{rep: e}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.ids = append(g.ids, e.ID)
+	}
+
+	for _, g := range groups {
+		s.call(g.rep.ID, g.rep.Event, func() { dd.handler(g.rep.ID, g.rep.Event, g.ids) })
+	}
+}
+
+// SetBatchHandler registers the handler called with the events expired
+// within one flush interval of WithAfterFuncBatching, including events
+// recovered from the DB. See Simple.SetBatchHandler.
+func (p *Persistent[ID, E]) SetBatchHandler(handler func(batch []Entry[ID, E])) {
+	p.s.SetBatchHandler(handler)
+}
+
+// SetDedupHandler collapses the events of a batch sharing the same key into
+// one call of handler. See Simple.SetDedupHandler.
+func (p *Persistent[ID, E]) SetDedupHandler(key func(E) string, handler func(id ID, event E, ids []ID)) {
+	p.s.SetDedupHandler(key, handler)
+}
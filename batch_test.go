@@ -0,0 +1,40 @@
+package timerstore
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSetDedupHandler(t *testing.T) {
+	s := NewSimple[string, testEvent](WithAfterFuncBatching(200 * time.Millisecond))
+	defer s.Close()
+
+	type call struct {
+		id  string
+		ids []string
+	}
+	calls := make(chan call, 4)
+	s.SetDedupHandler(func(e testEvent) string { return e.Name }, func(id string, _ testEvent, ids []string) {
+		calls <- call{id, ids}
+	})
+
+	now := time.Now()
+	for i, e := range []struct{ id, name string }{{"a", "x"}, {"b", "y"}, {"c", "x"}} {
+		if err := s.Start(e.id, testEvent{At: now.Add(time.Duration(i) * time.Millisecond), Name: e.name}, nil); err != nil {
+			t.Fatalf("Start(%q): %v", e.id, err)
+		}
+	}
+
+	want := []call{{"a", []string{"a", "c"}}, {"b", []string{"b"}}}
+	for _, w := range want {
+		select {
+		case got := <-calls:
+			if got.id != w.id || !slices.Equal(got.ids, w.ids) {
+				t.Errorf("handler called with %v; want %v", got, w)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("handler not called for %q", w.id)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package timerstore
+
+import "sync"
+
+const bloomHashes = 4
+
+// bloom is a bloom filter over hashed ids that forgets old entries. It keeps
+// two generations of bits and starts a new one whenever the current one has
+// taken as many entries as it can hold at about a 1% false-positive rate, so
+// membership means "added within roughly the last two generations".
+type bloom struct {
+	mu       sync.Mutex
+	cur, old []uint64
+	n, max   int
+}
+
+func newBloom(bits int) *bloom {
+	words := max(1, (bits+63)/64)
+	return &bloom{
+		cur: make([]uint64, words),
+		old: make([]uint64, words),
+		max: max(1, words*64/10),
+	}
+}
+
+func (b *bloom) add(h uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.n >= b.max {
+		b.old, b.cur = b.cur, b.old
+		clear(b.cur)
+		b.n = 0
+	}
+
+	for i := range uint64(bloomHashes) {
+		bit := b.bit(h, i)
+		b.cur[bit/64] |= 1 << (bit % 64)
+	}
+	b.n++
+}
+
+func (b *bloom) has(h uint64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.in(b.cur, h) || b.in(b.old, h)
+}
+
+func (b *bloom) in(bits []uint64, h uint64) bool {
+	for i := range uint64(bloomHashes) {
+		bit := b.bit(h, i)
+		if bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// bit returns the i-th bit position for h, using double hashing.
+func (b *bloom) bit(h, i uint64) uint64 {
+	h1, h2 := h, h>>32|h<<32|1
+	return (h1 + i*h2) % uint64(len(b.cur)*64)
+}
+
+// firedFilter remembers the ids of recently fired events. See
+// Persistent.SetFiredFilter.
+type firedFilter[ID comparable] struct {
+	bloom *bloom
+	hash  func(ID) uint64
+}
+
+// SetFiredFilter makes the store remember which ids fired recently, in a
+// bloom filter of size bits, and skip those ids in Recover. This cheaply
+// avoids firing an event twice when Recover runs while its row is still in
+// the DB, for instance because deletions are deferred (see
+// WithDeferredDelete), without a DB lookup per event. hash must spread ids
+// evenly over all 64 bits.
+//
+// The filter lives in memory only and forgets older ids as new ones fire. A
+// bloom filter can report false positives: with low probability, Recover
+// skips an event that never fired. Such an event is left in the DB, so a
+// later Recover, for instance after a restart, arms it again. Setting a filter
+// replaces the previous one, starting empty; a size of zero or less or a nil
+// hash removes it.
+func (p *Persistent[ID, E]) SetFiredFilter(size int, hash func(ID) uint64) {
+	if size <= 0 || hash == nil {
+		p.fired.Store(nil)
+		return
+	}
+
+	p.fired.Store(&firedFilter[ID]{bloom: newBloom(size), hash: hash})
+}
+
+func (f *firedFilter[ID]) add(id ID) { f.bloom.add(f.hash(id)) }
+
+func (f *firedFilter[ID]) has(id ID) bool { return f.bloom.has(f.hash(id)) }
@@ -0,0 +1,35 @@
+package timerstore
+
+import (
+	"context"
+	"hash/maphash"
+	"testing"
+	"time"
+)
+
+func TestSetFiredFilter(t *testing.T) {
+	db := newMapDB[testEvent]()
+	p := NewPersistentStore[string, testEvent](db)
+	defer p.Close()
+
+	seed := maphash.MakeSeed()
+	p.SetFiredFilter(1024, func(id string) uint64 { return maphash.String(seed, id) })
+
+	fired := make(chan struct{})
+	event := testEvent{At: time.Now()}
+	if err := p.Start("a", event, func() { close(fired) }); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	<-fired
+
+	// As if the deletion of the fired event was still deferred.
+	db.Put("a", event)
+	db.Put("b", event)
+	n, err := p.Recover(context.Background(), nil)
+	if err != nil || n != 1 {
+		t.Errorf("Recover = %d, %v; want only b recovered", n, err)
+	}
+	if !db.has("a") {
+		t.Error("skipped event deleted from the DB")
+	}
+}
@@ -0,0 +1,77 @@
+package timerstore
+
+import "context"
+
+// StartBound is like Start, but also cancels the event if ctx is done before
+// it expires. If ctx is already done, the event is not started and ctx.Err()
+// is returned.
+//
+// Each bound event costs one extra goroutine that waits for either ctx or the
+// removal of the event. It exits as soon as the event expires, is cancelled or
+// is replaced by another Start for the same id, so it never outlives the
+// event.
+func (s *Simple[ID, E]) StartBound(ctx context.Context, id ID, event E, atExpire func()) error {
+	return s.startBound(ctx, id, event, atExpire, nil)
+}
+
+// startBound starts a bound event and calls onCancel, if not nil, after the
+// event was cancelled because ctx was done.
+func (s *Simple[ID, E]) startBound(ctx context.Context, id ID, event E, atExpire func(), onCancel func(E)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	d, err := s.add(id, event, event.ExpireAt(), atExpire)
+	if err != nil {
+		s.mu.Unlock()
+		return kept(err)
+	}
+	d.done = make(chan struct{})
+	d.ctx, d.onCtxDone = ctx, onCancel
+	s.bind(id, d)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// bind starts the goroutine cancelling the bound event d of id once its
+// context is done, unless d left the store first. It must be called with s.mu
+// held.
+func (s *Simple[ID, E]) bind(id ID, d *data[E]) {
+	go func() {
+		select {
+		case <-d.ctx.Done():
+		case <-d.done:
+			return
+		}
+
+		s.mu.Lock()
+		if s.m[id] != d {
+			s.mu.Unlock()
+			return
+		}
+		s.cancel(id, d)
+		s.mu.Unlock()
+
+		if d.onCtxDone != nil {
+			d.onCtxDone(d.event)
+		}
+	}()
+}
+
+// StartBound is like Start, but also cancels the event, deleting it from the
+// persistent storage, if ctx is done before it expires. See Simple.StartBound.
+func (p *Persistent[ID, E]) StartBound(ctx context.Context, id ID, event E, atExpire func()) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := p.put(id, event); err != nil {
+		return kept(err)
+	}
+
+	return p.rollback(id, event, p.s.startBound(ctx, id, event, atExpire, func(event E) {
+		p.delete(id, event, ReasonCancelled)
+	}))
+}
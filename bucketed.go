@@ -0,0 +1,110 @@
+package timerstore
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Bucketed is a store that reports expiries in batches: instead of one
+// callback per event, the events expiring within the same fixed time bucket
+// are handed to a single flush callback once the bucket is over. Create one
+// with NewBucketed.
+//
+// Buckets are aligned to multiples of their size since the zero time, as by
+// time.Time.Truncate, so one-minute buckets start on the minute whatever the
+// events' spacing. An event goes into the bucket of the time it actually
+// fired, so an event firing late lands in a later bucket than its deadline.
+// A bucket is flushed by its own timer as soon as it ends, and only if at
+// least one event went into it.
+type Bucketed[ID comparable, E Event] struct {
+	s     Simple[ID, E]
+	size  time.Duration
+	flush func(bucketStart time.Time, events []Entry[ID, E])
+
+	mu      sync.Mutex
+	pending map[time.Time]*bucket[ID, E]
+}
+
+type bucket[ID comparable, E Event] struct {
+	events []Entry[ID, E]
+	timer  *time.Timer
+}
+
+// NewBucketed creates a Bucketed store with buckets of size, calling flush
+// with the start of each bucket and the events that expired in it, in the
+// order they expired. The in-memory store is configured with opts.
+func NewBucketed[ID comparable, E Event](size time.Duration, flush func(bucketStart time.Time, events []Entry[ID, E]), opts ...Option) *Bucketed[ID, E] {
+	b := &Bucketed[ID, E]{size: size, flush: flush}
+	b.s.init(newOptions(opts))
+	return b
+}
+
+// Start stores the event and sets a timer to add it to the current bucket
+// when it expires. See Simple.Start.
+func (b *Bucketed[ID, E]) Start(id ID, event E) error {
+	return b.s.Start(id, event, func() { b.add(id, event) })
+}
+
+// Cancel stops the timer for id and removes the event. An event already added
+// to a bucket is not taken out again.
+func (b *Bucketed[ID, E]) Cancel(id ID) (E, bool) { return b.s.Cancel(id) }
+
+// Get returns the event stored for id and whether it is present.
+func (b *Bucketed[ID, E]) Get(id ID) (E, bool) { return b.s.Get(id) }
+
+// Len returns the number of events stored, not counting expired events
+// waiting in a bucket.
+func (b *Bucketed[ID, E]) Len() int { return b.s.Len() }
+
+// Close closes the store as Simple.Close does, then flushes the buckets still
+// pending at once, oldest first.
+func (b *Bucketed[ID, E]) Close() error {
+	err := b.s.Close()
+
+	b.mu.Lock()
+	starts := make([]time.Time, 0, len(b.pending))
+	for start, bk := range b.pending {
+		if bk.timer.Stop() {
+			starts = append(starts, start)
+		}
+	}
+	b.mu.Unlock()
+
+	slices.SortFunc(starts, time.Time.Compare)
+	for _, start := range starts {
+		b.flushBucket(start)
+	}
+
+	return err
+}
+
+func (b *Bucketed[ID, E]) add(id ID, event E) {
+	now := time.Now()
+	start := now.Truncate(b.size)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bk, ok := b.pending[start]
+	if !ok {
+		if b.pending == nil {
+			b.pending = make(map[time.Time]*bucket[ID, E])
+		}
+		bk = &bucket[ID, E]{}
+		bk.timer = time.AfterFunc(start.Add(b.size).Sub(now), func() { b.flushBucket(start) })
+		b.pending[start] = bk
+	}
+	bk.events = append(bk.events, Entry[ID, E]{ID: id, Event: event})
+}
+
+func (b *Bucketed[ID, E]) flushBucket(start time.Time) {
+	b.mu.Lock()
+	bk := b.pending[start]
+	delete(b.pending, start)
+	b.mu.Unlock()
+
+	if bk != nil && b.flush != nil {
+		b.flush(start, bk.events)
+	}
+}
@@ -0,0 +1,66 @@
+package timerstore
+
+import (
+	"testing"
+	"time"
+)
+
+type flushed struct {
+	start  time.Time
+	events []Entry[string, testEvent]
+}
+
+func TestBucketedAligned(t *testing.T) {
+	const size = 50 * time.Millisecond
+	got := make(chan flushed, 2)
+	b := NewBucketed(size, func(start time.Time, events []Entry[string, testEvent]) {
+		got <- flushed{start, events}
+	})
+	defer b.Close()
+
+	// Start just after a bucket boundary, so both events fire in one bucket.
+	time.Sleep(time.Until(time.Now().Truncate(size).Add(size + 5*time.Millisecond)))
+	at := time.Now()
+	b.Start("a", testEvent{At: at})
+	b.Start("b", testEvent{At: at})
+
+	select {
+	case f := <-got:
+		if !f.start.Truncate(size).Equal(f.start) {
+			t.Errorf("bucket starts at %v; want a multiple of %v", f.start, size)
+		}
+		if at.Before(f.start) || !at.Before(f.start.Add(size)) {
+			t.Errorf("bucket of %v starts at %v; want the bucket of the deadline", at, f.start)
+		}
+		if len(f.events) != 2 || f.events[0].ID == f.events[1].ID {
+			t.Errorf("bucket holds %v; want a and b", f.events)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("bucket not flushed")
+	}
+}
+
+func TestBucketedLate(t *testing.T) {
+	const size = 50 * time.Millisecond
+	got := make(chan flushed, 1)
+	b := NewBucketed(size, func(start time.Time, events []Entry[string, testEvent]) {
+		got <- flushed{start, events}
+	})
+	defer b.Close()
+
+	// Holding the callback back makes the event fire two buckets late.
+	b.s.Pause()
+	at := time.Now()
+	b.Start("a", testEvent{At: at})
+	time.Sleep(2 * size)
+	b.s.Resume()
+
+	select {
+	case f := <-got:
+		if due := at.Truncate(size); !f.start.After(due) {
+			t.Errorf("late event in the bucket at %v; want one after its deadline's at %v", f.start, due)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("bucket not flushed")
+	}
+}
@@ -0,0 +1,65 @@
+// Package cache provides a TTL cache built on timerstore.
+package cache
+
+import (
+	"time"
+
+	"github.com/chanchal1987/timerstore"
+)
+
+// entry is the event stored for every cached value.
+type entry[V any] struct {
+	value    V
+	expireAt time.Time
+}
+
+func (e entry[V]) ExpireAt() time.Time { return e.expireAt }
+
+// Cache is a key-value cache whose entries are evicted automatically when
+// their TTL runs out. It is safe for concurrent use. The zero value is an
+// empty cache without an eviction callback.
+type Cache[K comparable, V any] struct {
+	s       timerstore.Simple[K, entry[V]]
+	onEvict func(key K, value V)
+}
+
+// New creates an empty cache. If onEvict is not nil, it is called with the key
+// and value of every entry evicted because its TTL ran out. It is not called
+// for entries removed by Delete or replaced by Set.
+func New[K comparable, V any](onEvict func(key K, value V)) *Cache[K, V] {
+	return &Cache[K, V]{onEvict: onEvict}
+}
+
+// Set stores value under key for ttl, replacing any previous value and TTL.
+func (c *Cache[K, V]) Set(key K, value V, ttl time.Duration) {
+	e := entry[V]{value: value, expireAt: time.Now().Add(ttl)}
+
+	// Simple.Start fails only once the cache is closed.
+	_ = c.s.Start(key, e, func() {
+		if c.onEvict != nil {
+			c.onEvict(key, value)
+		}
+	})
+}
+
+// Get returns the value stored under key and whether it is present.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	e, ok := c.s.Get(key)
+	return e.value, ok
+}
+
+// Delete removes key from the cache and reports whether it was present.
+func (c *Cache[K, V]) Delete(key K) bool {
+	_, ok := c.s.Cancel(key)
+	return ok
+}
+
+// Len returns the number of entries in the cache.
+func (c *Cache[K, V]) Len() int { return c.s.Len() }
+
+// Close stops the cache, dropping its entries without calling onEvict. Set
+// does nothing afterwards.
+func (c *Cache[K, V]) Close() {
+	// Simple.Close never fails.
+	_ = c.s.Close()
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetGetDelete(t *testing.T) {
+	c := New[string, int](nil)
+	defer c.Close()
+
+	c.Set("a", 1, time.Hour)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("Get = %v, %v; want 1, true", v, ok)
+	}
+	if !c.Delete("a") {
+		t.Error("Delete found no entry")
+	}
+	if _, ok := c.Get("a"); ok || c.Len() != 0 {
+		t.Error("deleted entry still cached")
+	}
+	if c.Delete("a") {
+		t.Error("Delete found an entry twice")
+	}
+}
+
+func TestEvict(t *testing.T) {
+	evicted := make(chan int, 1)
+	c := New(func(key string, value int) { evicted <- value })
+	defer c.Close()
+
+	c.Set("a", 1, 10*time.Millisecond)
+	select {
+	case v := <-evicted:
+		if v != 1 {
+			t.Errorf("onEvict got %d; want 1", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("entry not evicted")
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Error("evicted entry still cached")
+	}
+}
+
+func TestSetReplace(t *testing.T) {
+	evicted := make(chan int, 2)
+	c := New(func(key string, value int) { evicted <- value })
+	defer c.Close()
+
+	c.Set("a", 1, 10*time.Millisecond)
+	c.Set("a", 2, 30*time.Millisecond)
+	select {
+	case v := <-evicted:
+		if v != 2 {
+			t.Errorf("onEvict got %d; want only the replacing value 2", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("entry not evicted")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n := len(evicted); n != 0 {
+		t.Errorf("onEvict called %d more times; want none", n)
+	}
+}
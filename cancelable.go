@@ -0,0 +1,55 @@
+package timerstore
+
+// StartCancelable is like Start, but also returns a function that cancels
+// exactly the event started, like context.WithCancel does for a context. The
+// function returns the event and true if it cancelled it, and false once the
+// event has expired, been cancelled or been replaced by another Start for the
+// same id, so it is safe to call any number of times. If the stored event is
+// kept (see WithReplaceMode) or Start fails, the function does nothing.
+func (s *Simple[ID, E]) StartCancelable(id ID, event E, atExpire func()) (func() (E, bool), error) {
+	s.mu.Lock()
+	d, err := s.add(id, event, event.ExpireAt(), atExpire)
+	s.mu.Unlock()
+
+	if d == nil {
+		return noCancel[E], kept(err)
+	}
+
+	return func() (E, bool) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.m[id] != d {
+			var zeroE E
+			return zeroE, false
+		}
+
+		s.cancel(id, d)
+		return d.event, true
+	}, nil
+}
+
+// noCancel is the cancel function of an event that was not started.
+func noCancel[E Event]() (E, bool) {
+	var zeroE E
+	return zeroE, false
+}
+
+// StartCancelable is like Start, but also returns a function that cancels
+// exactly the event started, deleting it from the persistent storage. See
+// Simple.StartCancelable.
+func (p *Persistent[ID, E]) StartCancelable(id ID, event E, atExpire func()) (func() (E, bool), error) {
+	if err := p.put(id, event); err != nil {
+		return noCancel[E], kept(err)
+	}
+
+	cancel, err := p.s.StartCancelable(id, event, atExpire)
+	return func() (E, bool) {
+		event, ok := cancel()
+		if ok {
+			p.delete(id, event, ReasonCancelled)
+		}
+
+		return event, ok
+	}, p.rollback(id, event, err)
+}
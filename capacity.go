@@ -0,0 +1,32 @@
+package timerstore
+
+import "errors"
+
+// ErrCapacityExceeded is returned by Start when admitting an event would take
+// the store over its capacity (see WithCapacity).
+var ErrCapacityExceeded = errors.New("timerstore: capacity exceeded")
+
+// Weighter is implemented by events that take up a variable share of a store's
+// capacity, such as events whose memory footprint differs widely. Events that
+// do not implement it weigh 1. Weight must not change while the event is
+// stored.
+type Weighter interface {
+	Weight() int64
+}
+
+func weightOf[E Event](event E) int64 {
+	if w, ok := any(event).(Weighter); ok {
+		return w.Weight()
+	}
+
+	return 1
+}
+
+// CurrentWeight returns the total weight of the events currently stored. See
+// Weighter.
+func (s *Simple[ID, E]) CurrentWeight() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.weight
+}
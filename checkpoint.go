@@ -0,0 +1,128 @@
+package timerstore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// CheckpointStorage holds the checkpoint of a CheckpointStore, such as a file
+// or a single row of a database.
+type CheckpointStorage interface {
+	// Load returns a reader of the last checkpoint saved, or ErrNotFound if
+	// there is none.
+	Load() (io.ReadCloser, error)
+	// Save returns a writer for a new checkpoint, which replaces the last
+	// one once the writer is closed without error.
+	Save() (io.WriteCloser, error)
+}
+
+// CheckpointStore is an in-memory store that survives planned restarts
+// without writing to storage while it runs: its events are only saved, as a
+// checkpoint in the format of Snapshot, by Checkpoint and when the store is
+// closed, and loaded back by RestoreCheckpoint. A crash loses every change
+// made since the last checkpoint, and events that expired since are restored
+// and fire again. Create one with NewCheckpointStore.
+//
+// Callbacks cannot be saved, so events restored from a checkpoint go to the
+// handler set with SetExpiryHandler, like events started with a nil
+// atExpire.
+type CheckpointStore[ID comparable, E Event] struct {
+	s       Simple[ID, E]
+	storage CheckpointStorage
+	keys    KeyCodec[ID]
+	events  Codec[E]
+}
+
+var _ Store[any, Event] = &CheckpointStore[any, Event]{}
+
+// NewCheckpointStore creates a CheckpointStore saving its checkpoints to
+// storage, with ids encoded by keys and events by events. The in-memory
+// store is configured with opts. Call RestoreCheckpoint to load the events
+// saved by the previous run.
+func NewCheckpointStore[ID comparable, E Event](storage CheckpointStorage, keys KeyCodec[ID], events Codec[E], opts ...Option) *CheckpointStore[ID, E] {
+	c := &CheckpointStore[ID, E]{storage: storage, keys: keys, events: events}
+	c.s.init(newOptions(opts))
+	return c
+}
+
+// Start stores the event in memory. See Simple.Start.
+func (c *CheckpointStore[ID, E]) Start(id ID, event E, atExpire func()) error {
+	return c.s.Start(id, event, atExpire)
+}
+
+// Cancel stops the timer for id and removes the event. See Simple.Cancel.
+func (c *CheckpointStore[ID, E]) Cancel(id ID) (E, bool) { return c.s.Cancel(id) }
+
+// Get returns the event stored for id and whether it is present.
+func (c *CheckpointStore[ID, E]) Get(id ID) (E, bool) { return c.s.Get(id) }
+
+// Len returns the number of events currently stored.
+func (c *CheckpointStore[ID, E]) Len() int { return c.s.Len() }
+
+// SetExpiryHandler registers the handler called for restored events and for
+// events started with a nil atExpire. See Simple.SetExpiryHandler.
+func (c *CheckpointStore[ID, E]) SetExpiryHandler(handler func(id ID, event E)) {
+	c.s.SetExpiryHandler(handler)
+}
+
+// Checkpoint saves the events currently stored, with the deadlines their
+// timers are armed for, replacing the last checkpoint. The store keeps
+// running meanwhile.
+func (c *CheckpointStore[ID, E]) Checkpoint() error {
+	w, err := c.storage.Save()
+	if err != nil {
+		return fmt.Errorf("timerstore: save checkpoint: %w", err)
+	}
+
+	if err := c.s.Snapshot(w, c.keys, c.events); err != nil {
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
+// RestoreCheckpoint starts the events of the last checkpoint saved, armed for
+// their saved deadlines; events already past them fire immediately. It
+// returns the number of events started, which is also valid when an error
+// stops it partway. Without a checkpoint, it starts nothing and returns nil.
+func (c *CheckpointStore[ID, E]) RestoreCheckpoint() (int, error) {
+	r, err := c.storage.Load()
+	if errors.Is(err, ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("timerstore: load checkpoint: %w", err)
+	}
+	defer r.Close()
+
+	return c.s.Restore(r, c.keys, c.events, func(id ID, event E) {
+		if h := c.s.handler.Load(); h != nil {
+			(*h)(id, event)
+		}
+	})
+}
+
+// Close stops the store and saves the events still stored as the new
+// checkpoint. No event expires between the two: Start fails with ErrClosed
+// from the moment the events are taken, and Close waits for the callbacks of
+// earlier expiries before saving. Calling Close again does nothing.
+func (c *CheckpointStore[ID, E]) Close() error {
+	taken, ok := c.s.closeTaking()
+	if !ok {
+		return nil
+	}
+
+	w, err := c.storage.Save()
+	if err != nil {
+		return fmt.Errorf("timerstore: save checkpoint: %w", err)
+	}
+
+	if err := writeSnapshot(w, c.keys, c.events, taken); err != nil {
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
@@ -0,0 +1,196 @@
+package timerstore
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ErrClosed is returned by Start after the store was closed.
+var ErrClosed = errors.New("timerstore: store closed")
+
+// CloseMode selects what Close does with the events still stored.
+type CloseMode int
+
+const (
+	// CloseDrop stops the timers of all stored events without calling their
+	// callbacks.
+	CloseDrop CloseMode = iota
+	// CloseFlushPastDue calls the callbacks of the events whose deadline has
+	// passed, in deadline order, and stops the timers of the others. This
+	// covers events whose timer fired while Close was running.
+	CloseFlushPastDue
+	// CloseFlushAll calls the callbacks of all stored events in deadline
+	// order, whether or not their deadline has passed.
+	CloseFlushAll
+)
+
+// SetFlushOrder makes Close call the callbacks of flushed events in the order
+// of their ids given by less, instead of in deadline order, so that runs are
+// reproducible whatever the deadlines. Passing nil restores the deadline
+// order.
+//
+// Only Close orders the callbacks it runs itself. Events recovered past their
+// deadline by Recover expire on their own timers, concurrently, so their
+// callbacks run in no particular order.
+func (s *Simple[ID, E]) SetFlushOrder(less func(a, b ID) bool) {
+	if less == nil {
+		s.flushLess.Store(nil)
+		return
+	}
+
+	s.flushLess.Store(&less)
+}
+
+// SetFlushOrder makes Close call the callbacks of flushed events in the order
+// of their ids given by less. See Simple.SetFlushOrder.
+func (p *Persistent[ID, E]) SetFlushOrder(less func(a, b ID) bool) {
+	p.s.SetFlushOrder(less)
+}
+
+// Close stops the store. Start fails with ErrClosed afterwards. The events
+// still stored are removed and handled according to the close mode (see
+// WithCloseMode): flushed events count as expired and dropped events as
+// cancelled. Flushed callbacks run on the goroutine calling Close, bypassing
+// the expiry rate limit, in deadline order or in the order of their ids set
+// with SetFlushOrder. Close then waits for the callbacks of events that
+// expired earlier and are still running or queued, stops the worker pool, if
+// any, and returns. Calling Close again does nothing.
+func (s *Simple[ID, E]) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+
+	now := time.Now()
+	var flush []entry[ID, E]
+	for id, d := range s.m {
+		s.stop(d)
+
+		switch {
+		case s.opts.closeMode == CloseFlushAll,
+			s.opts.closeMode == CloseFlushPastDue && !d.deadline.After(now):
+			d.outcome = OutcomeExpired
+			flush = append(flush, entry[ID, E]{id, d})
+		default:
+			s.cancelled.Add(1)
+		}
+		s.remove(id, d)
+	}
+	held := s.backlog
+	s.backlog = nil
+	s.mu.Unlock()
+	s.stopAcks()
+
+	if s.opts.closeMode != CloseDrop {
+		slices.SortFunc(held, func(a, b entry[ID, E]) int { return a.d.deadline.Compare(b.d.deadline) })
+		for _, e := range held {
+			s.fire(e.id, e.d)
+		}
+	}
+
+	if fn := s.flushLess.Load(); fn != nil {
+		less := *fn
+		slices.SortFunc(flush, func(a, b entry[ID, E]) int {
+			switch {
+			case less(a.id, b.id):
+				return -1
+			case less(b.id, a.id):
+				return 1
+			default:
+				return 0
+			}
+		})
+	} else {
+		slices.SortFunc(flush, func(a, b entry[ID, E]) int { return a.d.deadline.Compare(b.d.deadline) })
+	}
+	for _, e := range flush {
+		s.expired.Add(1)
+		s.fire(e.id, e.d)
+	}
+
+	s.wait()
+	return nil
+}
+
+// wait waits for the callbacks still running or queued after the store was
+// closed, and stops the worker pool.
+func (s *Simple[ID, E]) wait() {
+	s.inflight.Wait()
+	if s.pool != nil {
+		s.pool.close()
+	}
+}
+
+// CloseInto closes the store like Close, but instead of dropping or flushing
+// the events still stored, it hands them over to successor: each event is
+// cancelled here and started in successor with the same id and event, so it
+// keeps its ExpireAt. handler, if not nil, supplies the callback of the event
+// in successor; otherwise the event keeps its callback. The events are started
+// after the callbacks of earlier expiries have finished, so successor must not
+// be the store being closed. CloseInto returns the errors of successor's
+// Start, joined, and does nothing if the store is already closed.
+//
+// Deadlines moved after Start, by Reschedule or Suspend for instance, are not
+// carried over unless the event implements ExpirySetter.
+func (s *Simple[ID, E]) CloseInto(successor Store[ID, E], handler func(id ID) func()) error {
+	moved, ok := s.closeTaking()
+	if !ok {
+		return nil
+	}
+
+	var errs []error
+	for _, e := range moved {
+		atExpire := e.d.atExpire
+		if handler != nil {
+			atExpire = handler(e.id)
+		}
+
+		if err := successor.Start(e.id, e.d.event, atExpire); err != nil {
+			errs = append(errs, fmt.Errorf("timerstore: hand over %v: %w", e.id, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// closeTaking closes the store, cancelling every stored event, and returns
+// them once the callbacks of earlier expiries have finished. ok is false if
+// the store is already closed.
+func (s *Simple[ID, E]) closeTaking() (taken []entry[ID, E], ok bool) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, false
+	}
+	s.closed = true
+
+	taken = make([]entry[ID, E], 0, len(s.m))
+	for id, d := range s.m {
+		s.cancel(id, d)
+		taken = append(taken, entry[ID, E]{id, d})
+	}
+	s.mu.Unlock()
+	s.stopAcks()
+	s.wait()
+
+	return taken, true
+}
+
+// Close stops the store and releases its resources. The in-memory store is
+// closed as by Simple.Close. Events dropped by the close mode stay in the DB,
+// so a later Recover re-arms them, while flushed events are deleted as they
+// fire. With WithDeferredDelete, Close then stops the background flusher and
+// flushes the pending deletions.
+func (p *Persistent[ID, E]) Close() error {
+	err := p.s.Close()
+
+	if p.deletes != nil {
+		p.deletes.close()
+	}
+
+	return err
+}
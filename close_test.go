@@ -0,0 +1,27 @@
+package timerstore
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSetFlushOrder(t *testing.T) {
+	s := NewSimple[string, testEvent](WithCloseMode(CloseFlushAll))
+	s.SetFlushOrder(func(a, b string) bool { return a < b })
+
+	var order []string
+	at := time.Now().Add(time.Hour)
+	for i, id := range []string{"c", "a", "b"} {
+		if err := s.Start(id, testEvent{At: at.Add(time.Duration(i) * time.Minute)}, func() { order = append(order, id) }); err != nil {
+			t.Fatalf("Start(%q): %v", id, err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if want := []string{"a", "b", "c"}; !slices.Equal(order, want) {
+		t.Errorf("flushed %v; want %v", order, want)
+	}
+}
@@ -0,0 +1,108 @@
+package timerstore
+
+import "time"
+
+// Coalescer is implemented by events that may stand for the same logical work
+// under different ids, such as "refresh user 7" scheduled from several code
+// paths. Simple.Start merges events sharing a non-empty CoalesceKey into one
+// timer:
+//
+//   - The first event started with a key holds the timer. Starting an event
+//     with the same key under another id stores nothing new: the id is
+//     coalesced into the holder, whose deadline moves to the new event's
+//     ExpireAt if that is earlier. The incoming event and its callback are
+//     dropped, so one callback, the holder's, fires for the whole group.
+//   - Get and Len see only the holder. Cancelling the holder or any
+//     coalesced id cancels the shared timer, and with it the whole group.
+//   - Once the holder expires or is cancelled, the group is gone, and the next
+//     event with the key starts a new one. Starting the holder's id again
+//     also starts a new group.
+//
+// Only Start and Push coalesce. The other ways to start an event, and
+// Persistent, which stores every event in the DB on its own, ignore the key.
+type Coalescer interface {
+	CoalesceKey() string
+}
+
+// group is a set of coalesced ids sharing one timer.
+type group[ID comparable] struct {
+	holder  ID
+	members []ID
+}
+
+func coalesceKey[E Event](event E) string {
+	if c, ok := any(event).(Coalescer); ok {
+		return c.CoalesceKey()
+	}
+
+	return ""
+}
+
+// coalesce merges event into the group of its coalescing key, if another id
+// holds one. It returns the holder with its previous and current deadline,
+// and whether the event was merged. It must be called with s.mu held.
+func (s *Simple[ID, E]) coalesce(id ID, event E) (holder ID, old, at time.Time, ok bool) {
+	key := coalesceKey(event)
+	if key == "" || s.closed {
+		return holder, old, at, false
+	}
+
+	g := s.groups[key]
+	if g == nil || g.holder == id {
+		return holder, old, at, false
+	}
+
+	if prev, ok := s.m[id]; ok {
+		s.stop(prev)
+		prev.outcome = OutcomeReplaced
+		s.remove(id, prev)
+	}
+	if s.aliases == nil {
+		s.aliases = make(map[ID]ID)
+	}
+	if s.aliases[id] != g.holder {
+		s.aliases[id] = g.holder
+		g.members = append(g.members, id)
+	}
+	s.started.Add(1)
+
+	d := s.m[g.holder]
+	old, at = d.deadline, d.deadline
+	if next := event.ExpireAt(); next.Before(old) {
+		at = next
+		s.moveDeadline(d, at)
+	}
+
+	return g.holder, old, at, true
+}
+
+// group makes d, just added for id, the holder of the group of its coalescing
+// key. It must be called with s.mu held.
+func (s *Simple[ID, E]) group(id ID, d *data[E]) {
+	key := coalesceKey(d.event)
+	if key == "" {
+		return
+	}
+
+	if s.groups == nil {
+		s.groups = make(map[string]*group[ID])
+	}
+	d.key = key
+	s.groups[key] = &group[ID]{holder: id}
+}
+
+// ungroup dissolves the group held by d, which is being removed for id. It
+// must be called with s.mu held.
+func (s *Simple[ID, E]) ungroup(id ID, d *data[E]) {
+	g := s.groups[d.key]
+	if g == nil || g.holder != id {
+		return
+	}
+
+	for _, m := range g.members {
+		if s.aliases[m] == id {
+			delete(s.aliases, m)
+		}
+	}
+	delete(s.groups, d.key)
+}
@@ -0,0 +1,30 @@
+package timerstore
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func FuzzCheckCodec(f *testing.F) {
+	f.Add(time.Time{}.Unix(), int64(0), "")
+	f.Add(int64(-1), int64(1), "pre-epoch")
+	f.Add(time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC).Unix(), int64(500), "1900")
+	f.Add(time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC).Unix(), int64(999999999), "far future")
+	f.Add(time.Now().Unix(), int64(123456789), "now")
+
+	f.Fuzz(func(t *testing.T, sec, nsec int64, name string) {
+		event := testEvent{At: time.Unix(sec, nsec).UTC(), Name: name}
+		if err := CheckCodec[testEvent](GobCodec[testEvent]{}, event); err != nil {
+			t.Errorf("GobCodec: %v", err)
+		}
+
+		if _, err := json.Marshal(event); err != nil {
+			// The time is outside the years RFC 3339 can represent.
+			return
+		}
+		if err := CheckCodec[testEvent](JSONCodec[testEvent]{}, event); err != nil {
+			t.Errorf("JSONCodec: %v", err)
+		}
+	})
+}
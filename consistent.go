@@ -0,0 +1,409 @@
+package timerstore
+
+import (
+	"cmp"
+	"errors"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrPartitionExists is returned by AddPartition for a name already in use.
+var ErrPartitionExists = errors.New("timerstore: partition exists")
+
+// ErrNoPartition is returned for a partition name that is not in use, and by
+// Start on a store without partitions.
+var ErrNoPartition = errors.New("timerstore: no such partition")
+
+// Transfer moves the event stored for id to another store, keeping its
+// deadline, its callback and what is attached to it: Await and Done go on
+// waiting for it in to, a binding made with StartBound carries over, as do
+// the events waiting for it after StartAfterEvent, suspension, grace periods
+// and stages. Neither store sees the move as a cancellation; Stats count it
+// as a Start in to. Ids coalesced into the event are dropped. It reports
+// whether id was present.
+//
+// If to does not admit the event, Transfer fails with the error Start would
+// return, or with ErrExists if the replace mode keeps the event to holds for
+// id, and puts the event back. If that fails too, because s was closed or
+// started id again meanwhile, the event is cancelled. The two stores are
+// never locked together, so for a moment the event is in neither.
+func (s *Simple[ID, E]) Transfer(id ID, to *Simple[ID, E]) (bool, error) {
+	if to == s {
+		_, ok := s.peek(id)
+		return ok, nil
+	}
+
+	s.mu.Lock()
+	d, deps, ok := s.take(id)
+	s.mu.Unlock()
+	if !ok {
+		return false, nil
+	}
+
+	to.mu.Lock()
+	err := to.receive(id, d, deps)
+	to.mu.Unlock()
+	if err == nil {
+		return true, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if back := s.receive(id, d, deps); back != nil {
+		s.adopt(deps)
+		d.outcome = OutcomeCancelled
+		if d.done != nil {
+			close(d.done)
+		}
+		s.cancelled.Add(1)
+		s.follow(id, OutcomeCancelled)
+		return true, errors.Join(err, back)
+	}
+
+	return true, err
+}
+
+// take removes the event stored for id to be moved to another store, with
+// the events waiting for it, directly or down a chain, and reports whether it
+// was present. Unlike cancel, it leaves the events waiting, Await callers and
+// the counters alone. It must be called with s.mu held.
+func (s *Simple[ID, E]) take(id ID) (*data[E], map[ID]*dependent[ID, E], bool) {
+	d, ok := s.m[id]
+	if !ok {
+		return nil, nil, false
+	}
+
+	s.stop(d)
+	delete(s.m, id)
+	s.weight -= d.weight
+	if d.pinned {
+		s.pinned--
+	}
+	if d.key != "" {
+		s.ungroup(id, d)
+		d.key = ""
+	}
+
+	var deps map[ID]*dependent[ID, E]
+	for next := []ID{id}; len(next) > 0; next = next[1:] {
+		for _, w := range s.waiting[next[0]] {
+			if deps == nil {
+				deps = make(map[ID]*dependent[ID, E])
+			}
+			deps[w] = s.pending[w]
+			delete(s.pending, w)
+			next = append(next, w)
+		}
+		delete(s.waiting, next[0])
+	}
+
+	return d, deps, true
+}
+
+// receive stores d, taken from another store for id, with the events waiting
+// for it, and arms its timer. It fails, changing nothing, if the store does
+// not admit the event. It must be called with s.mu held.
+func (s *Simple[ID, E]) receive(id ID, d *data[E], deps map[ID]*dependent[ID, E]) error {
+	err := s.admit(id, d.event, d.deadline)
+	if err == errEvict {
+		err = s.evict(id, d.event, d.deadline)
+	}
+	if err == errKeep {
+		err = ErrExists
+	}
+	if err != nil {
+		return err
+	}
+
+	if s.m == nil {
+		s.m = make(map[ID]*data[E])
+	}
+	if prev, ok := s.m[id]; ok {
+		s.stop(prev)
+		prev.outcome = OutcomeReplaced
+		s.remove(id, prev)
+	}
+	delete(s.aliases, id)
+
+	now := time.Now()
+	wait := s.coarsen(now, s.delay(now, d.deadline))
+	s.seq++
+	d.seq = s.seq
+	d.armedAt, d.resetAt = now.Add(wait), now
+	s.armed.Add(1)
+	seq := d.seq
+	d.timer = time.AfterFunc(wait, func() { s.expire(id, d, seq) })
+	if d.suspended {
+		// As for Suspend, expire leaves alone a timer that fired first.
+		s.stop(d)
+	}
+	s.m[id] = d
+	s.weight += d.weight
+	if d.pinned {
+		s.pinned++
+	}
+	s.started.Add(1)
+	if n := int64(len(s.m)); n > s.peak.Load() {
+		s.peak.Store(n)
+	}
+
+	s.adopt(deps)
+	if d.ctx != nil {
+		s.bind(id, d)
+	}
+
+	return nil
+}
+
+// adopt registers the waiting events deps, taken from another store. It must
+// be called with s.mu held.
+func (s *Simple[ID, E]) adopt(deps map[ID]*dependent[ID, E]) {
+	if len(deps) == 0 {
+		return
+	}
+
+	if s.pending == nil {
+		s.pending = make(map[ID]*dependent[ID, E])
+		s.waiting = make(map[ID][]ID)
+	}
+	for w, dep := range deps {
+		if prev, ok := s.pending[w]; ok {
+			s.unwait(w, prev.after)
+		}
+		s.pending[w] = dep
+		s.waiting[dep.after] = append(s.waiting[dep.after], w)
+	}
+}
+
+var _ Store[any, Event] = &ConsistentPartitioned[any, Event]{}
+
+// ConsistentPartitioned is a Store that spreads ids over named Simple
+// partitions by consistent hashing, so that partitions can be added and
+// removed at run time while moving only the events whose owner changes. Each
+// partition is placed on a hash ring at several points, its replicas, and an
+// id belongs to the partition at the first point at or after the id's hash.
+//
+// Adding or removing a partition rebalances at once: the events that change
+// owner are moved with Transfer. On average only 1/k of the events move when
+// going to k partitions, but finding them checks every event of the store
+// when adding, or of the removed partition when removing, and blocks all
+// other operations on the store while it runs.
+type ConsistentPartitioned[ID comparable, E Event] struct {
+	hash     func(ID) uint64
+	replicas int
+	opts     []Option
+
+	mu    sync.RWMutex
+	ring  []ringPoint
+	parts map[string]*Simple[ID, E]
+}
+
+type ringPoint struct {
+	hash uint64
+	name string
+}
+
+// NewConsistentPartitioned creates a ConsistentPartitioned store without any
+// partition. hash must return the same value for the same id every time.
+// Every partition is placed on the ring replicas times, at least once, and is
+// a Simple configured with opts.
+func NewConsistentPartitioned[ID comparable, E Event](hash func(ID) uint64, replicas int, opts ...Option) *ConsistentPartitioned[ID, E] {
+	return &ConsistentPartitioned[ID, E]{
+		hash:     hash,
+		replicas: max(replicas, 1),
+		opts:     opts,
+		parts:    make(map[string]*Simple[ID, E]),
+	}
+}
+
+// AddPartition adds a partition named name and moves to it the events it now
+// owns.
+func (c *ConsistentPartitioned[ID, E]) AddPartition(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.parts[name]; ok {
+		return ErrPartitionExists
+	}
+
+	s := NewSimple[ID, E](c.opts...)
+	c.parts[name] = s
+	for i := 0; i < c.replicas; i++ {
+		c.ring = append(c.ring, ringPoint{pointHash(name, i), name})
+	}
+	slices.SortFunc(c.ring, func(a, b ringPoint) int { return cmp.Compare(a.hash, b.hash) })
+
+	var errs []error
+	for other, p := range c.parts {
+		if other == name {
+			continue
+		}
+		for _, id := range p.Keys() {
+			if c.owner(id) == name {
+				if _, err := p.Transfer(id, s); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// RemovePartition moves the events of the partition named name to their new
+// owners, then removes and closes it. The last partition cannot be removed
+// while it holds events.
+func (c *ConsistentPartitioned[ID, E]) RemovePartition(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p, ok := c.parts[name]
+	if !ok {
+		return ErrNoPartition
+	}
+	if len(c.parts) == 1 && p.Len() > 0 {
+		return errors.New("timerstore: cannot remove the last partition holding events")
+	}
+
+	delete(c.parts, name)
+	c.ring = slices.DeleteFunc(c.ring, func(pt ringPoint) bool { return pt.name == name })
+
+	var errs []error
+	for _, id := range p.Keys() {
+		if _, err := p.Transfer(id, c.parts[c.owner(id)]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, p.Close())
+
+	return errors.Join(errs...)
+}
+
+// Partitions returns the names of the partitions, in no particular order.
+func (c *ConsistentPartitioned[ID, E]) Partitions() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.parts))
+	for name := range c.parts {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// owner returns the name of the partition owning id. It must be called with
+// c.mu held and at least one partition.
+func (c *ConsistentPartitioned[ID, E]) owner(id ID) string {
+	h := c.hash(id)
+	i, _ := slices.BinarySearchFunc(c.ring, h, func(pt ringPoint, h uint64) int { return cmp.Compare(pt.hash, h) })
+	if i == len(c.ring) {
+		i = 0
+	}
+
+	return c.ring[i].name
+}
+
+func pointHash(name string, replica int) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write(strconv.AppendInt(nil, int64(replica), 10))
+	return h.Sum64()
+}
+
+// of returns the partition owning id, or nil if there is none. It must be
+// called with c.mu held.
+func (c *ConsistentPartitioned[ID, E]) of(id ID) *Simple[ID, E] {
+	if len(c.ring) == 0 {
+		return nil
+	}
+
+	return c.parts[c.owner(id)]
+}
+
+// Start starts the event in the partition owning id. It fails with
+// ErrNoPartition if there is no partition.
+func (c *ConsistentPartitioned[ID, E]) Start(id ID, event E, atExpire func()) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s := c.of(id)
+	if s == nil {
+		return ErrNoPartition
+	}
+
+	return s.Start(id, event, atExpire)
+}
+
+// Cancel cancels the event of id in the partition owning it.
+func (c *ConsistentPartitioned[ID, E]) Cancel(id ID) (E, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s := c.of(id)
+	if s == nil {
+		var zeroE E
+		return zeroE, false
+	}
+
+	return s.Cancel(id)
+}
+
+// Get returns the event stored for id in the partition owning it.
+func (c *ConsistentPartitioned[ID, E]) Get(id ID) (E, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s := c.of(id)
+	if s == nil {
+		var zeroE E
+		return zeroE, false
+	}
+
+	return s.Get(id)
+}
+
+// Reschedule moves the deadline of id in the partition owning it.
+func (c *ConsistentPartitioned[ID, E]) Reschedule(id ID, at time.Time) (E, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s := c.of(id)
+	if s == nil {
+		var zeroE E
+		return zeroE, false
+	}
+
+	return s.Reschedule(id, at)
+}
+
+// Len returns the number of events stored across all partitions.
+func (c *ConsistentPartitioned[ID, E]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	n := 0
+	for _, s := range c.parts {
+		n += s.Len()
+	}
+
+	return n
+}
+
+// Close closes every partition.
+func (c *ConsistentPartitioned[ID, E]) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	for _, s := range c.parts {
+		errs = append(errs, s.Close())
+	}
+
+	return errors.Join(errs...)
+}
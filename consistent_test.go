@@ -0,0 +1,147 @@
+package timerstore
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTransferKeepsState(t *testing.T) {
+	from, to := NewSimple[string, testEvent](), NewSimple[string, testEvent]()
+	defer from.Close()
+	defer to.Close()
+
+	fired := make(chan string, 2)
+	at := time.Now().Add(50 * time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := from.StartBound(ctx, "a", testEvent{At: at}, func() { fired <- "a" }); err != nil {
+		t.Fatalf("StartBound: %v", err)
+	}
+	if err := from.StartAfterEvent("b", "a", 0, testEvent{At: at}, func() { fired <- "b" }); err != nil {
+		t.Fatalf("StartAfterEvent: %v", err)
+	}
+	done := from.Done("a")
+
+	if ok, err := from.Transfer("a", to); !ok || err != nil {
+		t.Fatalf("Transfer = %v, %v; want true, nil", ok, err)
+	}
+	select {
+	case <-done:
+		t.Fatal("Done closed by the transfer")
+	default:
+	}
+	if st := from.Stats(); st.Cancelled != 0 || st.Len != 0 {
+		t.Errorf("source Stats = %+v; want nothing cancelled or left", st)
+	}
+	if _, ok := to.Get("a"); !ok {
+		t.Fatal("event not stored in the destination")
+	}
+
+	for _, want := range []string{"a", "b"} {
+		select {
+		case got := <-fired:
+			if got != want {
+				t.Errorf("%q fired; want %q", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%q never fired", want)
+		}
+	}
+	<-done
+	for _, s := range []*Simple[string, testEvent]{from, to} {
+		if err := s.Validate(); err != nil {
+			t.Errorf("Validate: %v", err)
+		}
+	}
+}
+
+func TestTransferBinding(t *testing.T) {
+	from, to := NewSimple[string, testEvent](), NewSimple[string, testEvent]()
+	defer from.Close()
+	defer to.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := from.StartBound(ctx, "a", testEvent{At: time.Now().Add(time.Hour)}, func() {}); err != nil {
+		t.Fatalf("StartBound: %v", err)
+	}
+	if _, err := from.Transfer("a", to); err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+
+	cancel()
+	select {
+	case <-to.Done("a"):
+	case <-time.After(5 * time.Second):
+		t.Fatal("binding did not carry over")
+	}
+}
+
+func TestTransferRejected(t *testing.T) {
+	from := NewSimple[string, testEvent]()
+	to := NewSimple[string, testEvent](WithReplaceMode(ReplaceReject))
+	defer from.Close()
+	defer to.Close()
+
+	at := time.Now().Add(time.Hour)
+	from.Start("a", testEvent{At: at, Name: "from"}, func() {})
+	to.Start("a", testEvent{At: at, Name: "to"}, func() {})
+
+	ok, err := from.Transfer("a", to)
+	if !ok || !errors.Is(err, ErrExists) {
+		t.Errorf("Transfer = %v, %v; want true, ErrExists", ok, err)
+	}
+	if got, ok := from.Get("a"); !ok || got.Name != "from" {
+		t.Errorf("source holds %v, %v; want the event put back", got, ok)
+	}
+	if got, _ := to.Get("a"); got.Name != "to" {
+		t.Errorf("destination holds %v; want its own event", got)
+	}
+	if err := from.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+func TestTransferRejectedFiresOnce(t *testing.T) {
+	from := NewSimple[string, testEvent]()
+	to := NewSimple[string, testEvent](WithReplaceMode(ReplaceReject))
+	defer from.Close()
+	defer to.Close()
+
+	// The events are due as they are transferred, so the timer taken with an
+	// event may fire while the event is put back.
+	const n = 100
+	var calls atomic.Int32
+	done := make(chan struct{})
+	at := time.Now().Add(time.Millisecond)
+	for i := range n {
+		id := strconv.Itoa(i)
+		to.Start(id, testEvent{At: time.Now().Add(time.Hour)}, func() {})
+		from.Start(id, testEvent{At: at}, func() {
+			if calls.Add(1) == n {
+				close(done)
+			}
+		})
+	}
+	for i := range n {
+		if _, err := from.Transfer(strconv.Itoa(i), to); !errors.Is(err, ErrExists) {
+			t.Fatalf("Transfer: %v; want ErrExists", err)
+		}
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("%d of %d events fired", calls.Load(), n)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := calls.Load(); got != n {
+		t.Errorf("atExpire called %d times; want %d", got, n)
+	}
+	if err := from.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package timerstore
+
+import "time"
+
+// StartedCount returns the number of events started since the store was
+// created. Starting an id that is already present counts again.
+func (s *Simple[ID, E]) StartedCount() uint64 { return s.started.Load() }
+
+// CancelledCount returns the number of events removed by Cancel since the
+// store was created.
+func (s *Simple[ID, E]) CancelledCount() uint64 { return s.cancelled.Load() }
+
+// ExpiredCount returns the number of events that expired since the store was
+// created. An event is counted when it leaves the store, just before its
+// callback is dispatched.
+func (s *Simple[ID, E]) ExpiredCount() uint64 { return s.expired.Load() }
+
+// HighWaterMark returns the largest number of events the store has held at
+// once since it was created or since the last ResetHighWaterMark.
+func (s *Simple[ID, E]) HighWaterMark() int { return int(s.peak.Load()) }
+
+// ResetHighWaterMark resets the high-water mark to the number of events
+// currently stored.
+func (s *Simple[ID, E]) ResetHighWaterMark() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.peak.Store(int64(len(s.m)))
+}
+
+// Stats is a point-in-time summary of the activity of a store.
+type Stats struct {
+	Started, Cancelled, Expired uint64
+	Len, HighWaterMark          int
+
+	// MaxLateness and AvgLateness measure how long after their deadline
+	// expired events were taken out of the store, over every expiry since
+	// the store was created. Events flushed by Close are not included.
+	MaxLateness, AvgLateness time.Duration
+}
+
+// Stats returns a summary of the activity of the store. The fields are read
+// one after another, so they are not a consistent snapshot while the store is
+// in use.
+func (s *Simple[ID, E]) Stats() Stats {
+	st := Stats{
+		Started:       s.started.Load(),
+		Cancelled:     s.cancelled.Load(),
+		Expired:       s.expired.Load(),
+		Len:           s.Len(),
+		HighWaterMark: s.HighWaterMark(),
+		MaxLateness:   time.Duration(s.lateMax.Load()),
+	}
+	if n := s.lateN.Load(); n > 0 {
+		st.AvgLateness = time.Duration(s.lateSum.Load() / int64(n))
+	}
+
+	return st
+}
+
+// OnLate registers fn to be called for every expired event with how late it
+// fired: the time between its deadline and the moment it was taken out of the
+// store, before its callback is dispatched. Under load timers can fire
+// noticeably late, and this makes it visible. fn runs on the timer goroutine
+// and delays the callback, so it must be quick. Passing nil removes the hook.
+func (s *Simple[ID, E]) OnLate(fn func(id ID, lateness time.Duration)) {
+	if fn == nil {
+		s.onLate.Store(nil)
+		return
+	}
+
+	s.onLate.Store(&fn)
+}
+
+// late records the lateness of an expired event and calls the OnLate hook.
+func (s *Simple[ID, E]) late(id ID, lateness time.Duration) {
+	s.lateN.Add(1)
+	s.lateSum.Add(int64(lateness))
+	for cur := s.lateMax.Load(); int64(lateness) > cur; cur = s.lateMax.Load() {
+		if s.lateMax.CompareAndSwap(cur, int64(lateness)) {
+			break
+		}
+	}
+
+	if fn := s.onLate.Load(); fn != nil {
+		(*fn)(id, lateness)
+	}
+}
+
+// StartedCount returns the number of events started. See Simple.StartedCount.
+func (p *Persistent[ID, E]) StartedCount() uint64 { return p.s.StartedCount() }
+
+// CancelledCount returns the number of events cancelled. See
+// Simple.CancelledCount.
+func (p *Persistent[ID, E]) CancelledCount() uint64 { return p.s.CancelledCount() }
+
+// ExpiredCount returns the number of events expired. See Simple.ExpiredCount.
+func (p *Persistent[ID, E]) ExpiredCount() uint64 { return p.s.ExpiredCount() }
+
+// HighWaterMark returns the peak number of events held in memory. See
+// Simple.HighWaterMark.
+func (p *Persistent[ID, E]) HighWaterMark() int { return p.s.HighWaterMark() }
+
+// ResetHighWaterMark resets the high-water mark. See
+// Simple.ResetHighWaterMark.
+func (p *Persistent[ID, E]) ResetHighWaterMark() { p.s.ResetHighWaterMark() }
+
+// Stats returns a summary of the activity of the in-memory store. See
+// Simple.Stats.
+func (p *Persistent[ID, E]) Stats() Stats { return p.s.Stats() }
+
+// OnLate registers fn to be called with the lateness of every expired event.
+// See Simple.OnLate.
+func (p *Persistent[ID, E]) OnLate(fn func(id ID, lateness time.Duration)) { p.s.OnLate(fn) }
@@ -0,0 +1,128 @@
+package timerstore
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned by a Getter for an id it does not store.
+var ErrNotFound = errors.New("timerstore: not found")
+
+// Entry pairs an id with its event.
+type Entry[ID any, E Event] struct {
+	ID    ID
+	Event E
+}
+
+// The interfaces below are optional capabilities of a DB. Features that need
+// one check for it at run time and degrade gracefully, or report an error,
+// when the DB does not implement it.
+
+// Lister is implemented by a DB that can enumerate every event it stores. It
+// is required by Persistent.Recover.
+type Lister[ID any, E Event] interface {
+	List() ([]Entry[ID, E], error)
+}
+
+// Getter is implemented by a DB that can look up a single event. Get returns
+// ErrNotFound if the DB has no event for id.
+type Getter[ID any, E Event] interface {
+	Get(id ID) (E, error)
+}
+
+// BatchPutter is implemented by a DB that can store many events in one
+// operation.
+type BatchPutter[ID any, E Event] interface {
+	PutBatch(entries []Entry[ID, E]) error
+}
+
+// BatchDeleter is implemented by a DB that can delete many events in one
+// operation. It is used by WithDeferredDelete, and since only expired events
+// are deferred, every event in a batch is deleted for ReasonExpired.
+type BatchDeleter[ID any, E Event] interface {
+	DeleteBatch(entries []Entry[ID, E])
+}
+
+// Reason tells a ReasonDeleter why an event is deleted.
+type Reason int
+
+const (
+	// ReasonExpired is passed for an event deleted because it expired.
+	ReasonExpired Reason = iota + 1
+	// ReasonCancelled is passed for an event deleted because it was
+	// cancelled.
+	ReasonCancelled
+)
+
+// String returns the name of the reason.
+func (r Reason) String() string {
+	switch r {
+	case ReasonExpired:
+		return "expired"
+	case ReasonCancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+// ReasonDeleter is implemented by a DB that wants to know why an event is
+// deleted, for example to keep an audit log. Persistent calls DeleteWithReason
+// instead of Delete when the DB implements it.
+type ReasonDeleter[ID any, E Event] interface {
+	DeleteWithReason(id ID, event E, reason Reason)
+}
+
+// CheckedDeleter is implemented by a DB whose deletions can fail and that
+// reports them. Persistent.CancelChecked calls DeleteChecked, with the
+// reason, instead of Delete or DeleteWithReason when the DB implements it.
+type CheckedDeleter[ID any, E Event] interface {
+	DeleteChecked(id ID, event E, reason Reason) error
+}
+
+func deleteFrom[ID any, E Event](db DB[ID, E], id ID, event E, reason Reason) {
+	if rd, ok := db.(ReasonDeleter[ID, E]); ok {
+		rd.DeleteWithReason(id, event, reason)
+		return
+	}
+
+	db.Delete(id, event)
+}
+
+// DBCapabilities reports which optional interfaces a DB implements.
+type DBCapabilities struct {
+	List        bool // Lister
+	Get         bool // Getter
+	PutBatch    bool // BatchPutter
+	DeleteBatch bool // BatchDeleter
+	Reason      bool // ReasonDeleter
+	Checked     bool // CheckedDeleter
+	Close       bool // io.Closer
+}
+
+// Capabilities returns the optional interfaces implemented by db.
+func Capabilities[ID any, E Event](db DB[ID, E]) DBCapabilities {
+	_, list := db.(Lister[ID, E])
+	_, get := db.(Getter[ID, E])
+	_, putBatch := db.(BatchPutter[ID, E])
+	_, deleteBatch := db.(BatchDeleter[ID, E])
+	_, reason := db.(ReasonDeleter[ID, E])
+	_, checked := db.(CheckedDeleter[ID, E])
+	_, closer := db.(io.Closer)
+
+	return DBCapabilities{
+		List:        list,
+		Get:         get,
+		PutBatch:    putBatch,
+		DeleteBatch: deleteBatch,
+		Reason:      reason,
+		Checked:     checked,
+		Close:       closer,
+	}
+}
+
+// errNotImplemented returns the error reported when a feature needs the
+// optional interface name that the DB does not implement.
+func errNotImplemented(name string) error {
+	return errors.New("timerstore: DB does not implement " + name)
+}
@@ -0,0 +1,79 @@
+package timerstore
+
+import (
+	"sync"
+	"time"
+)
+
+// deferredDeletes accumulates DB deletions and flushes them in batches.
+type deferredDeletes[ID any, E Event] struct {
+	db       DB[ID, E]
+	maxBatch int
+	stop     chan struct{}
+	stopped  sync.WaitGroup
+	once     sync.Once
+
+	mu      sync.Mutex
+	pending []Entry[ID, E]
+}
+
+func newDeferredDeletes[ID any, E Event](db DB[ID, E], interval time.Duration, maxBatch int) *deferredDeletes[ID, E] {
+	dd := &deferredDeletes[ID, E]{db: db, maxBatch: maxBatch, stop: make(chan struct{})}
+
+	dd.stopped.Add(1)
+	go func() {
+		defer dd.stopped.Done()
+
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-t.C:
+				dd.flush()
+			case <-dd.stop:
+				return
+			}
+		}
+	}()
+
+	return dd
+}
+
+func (dd *deferredDeletes[ID, E]) add(id ID, event E) {
+	dd.mu.Lock()
+	dd.pending = append(dd.pending, Entry[ID, E]{ID: id, Event: event})
+	full := dd.maxBatch > 0 && len(dd.pending) >= dd.maxBatch
+	dd.mu.Unlock()
+
+	if full {
+		dd.flush()
+	}
+}
+
+func (dd *deferredDeletes[ID, E]) flush() {
+	dd.mu.Lock()
+	batch := dd.pending
+	dd.pending = nil
+	dd.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	if bd, ok := dd.db.(BatchDeleter[ID, E]); ok {
+		bd.DeleteBatch(batch)
+		return
+	}
+
+	for _, e := range batch {
+		deleteFrom(dd.db, e.ID, e.Event, ReasonExpired)
+	}
+}
+
+// close stops the background flusher and flushes the pending deletions.
+func (dd *deferredDeletes[ID, E]) close() {
+	dd.once.Do(func() { close(dd.stop) })
+	dd.stopped.Wait()
+	dd.flush()
+}
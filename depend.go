@@ -0,0 +1,139 @@
+package timerstore
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNoPredecessor is returned by StartAfterEvent when the event to follow is
+// neither stored nor itself waiting for another.
+var ErrNoPredecessor = errors.New("timerstore: no event to follow")
+
+// ErrDependencyCycle is returned by StartAfterEvent when the event to follow
+// waits, directly or down a chain, for the event being registered.
+var ErrDependencyCycle = errors.New("timerstore: dependency cycle")
+
+// DependentMode selects what happens to the events waiting, after
+// StartAfterEvent, for an event that leaves the store without expiring.
+type DependentMode int
+
+const (
+	// DependentCancel cancels them, and the events waiting for them in
+	// turn, so a chain stops at the first cancelled link.
+	DependentCancel DependentMode = iota
+	// DependentKeep arms them all the same, as if the event they wait for
+	// expired when it left the store.
+	DependentKeep
+)
+
+// dependent is an event waiting for another to expire before being armed.
+type dependent[ID comparable, E Event] struct {
+	after ID
+	delay time.Duration
+	d     *data[E] // not armed
+}
+
+// StartAfterEvent registers event to be started delay after the event stored
+// for afterID expires, for chains of steps in a workflow. Until then the event
+// waits outside the store: Get and Len do not see it, but Cancel removes it.
+// Its timer is armed at the moment the event for afterID leaves the store on
+// expiry, just before its callback runs, and afterwards it is an event like
+// any other. If the event for afterID leaves the store otherwise, because it
+// was cancelled or the store closed, the mode set with WithDependentMode
+// decides. An event replacing the one for afterID takes over its waiting
+// events.
+//
+// afterID may itself be waiting, so that events can be chained. Registering
+// id again replaces its previous registration, which could close a cycle of
+// events waiting for each other, never to be armed; StartAfterEvent detects
+// it by walking the chain from afterID, which costs O(length of the chain),
+// and fails with ErrDependencyCycle. It fails with ErrNoPredecessor if
+// afterID is neither stored nor waiting, or equals id, and with ErrClosed
+// after Close. Admission checks, such as the capacity, apply when the event
+// is armed; an event they reject is dropped. Stats only count an event once
+// armed, so one cancelled or dropped while waiting is not counted at all.
+func (s *Simple[ID, E]) StartAfterEvent(id ID, afterID ID, delay time.Duration, event E, atExpire func()) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	_, stored := s.m[afterID]
+	_, waiting := s.pending[afterID]
+	if id == afterID || !stored && !waiting {
+		return ErrNoPredecessor
+	}
+	for dep, ok := s.pending[afterID]; ok; dep, ok = s.pending[dep.after] {
+		if dep.after == id {
+			return ErrDependencyCycle
+		}
+	}
+
+	if prev, ok := s.pending[id]; ok {
+		s.unwait(id, prev.after)
+	}
+	if s.pending == nil {
+		s.pending = make(map[ID]*dependent[ID, E])
+		s.waiting = make(map[ID][]ID)
+	}
+	s.pending[id] = &dependent[ID, E]{after: afterID, delay: delay, d: &data[E]{event: event, atExpire: atExpire}}
+	s.waiting[afterID] = append(s.waiting[afterID], id)
+
+	return nil
+}
+
+// unwait takes id off the list of events waiting for after. It must be called
+// with s.mu held.
+func (s *Simple[ID, E]) unwait(id, after ID) {
+	ids := s.waiting[after]
+	for i, w := range ids {
+		if w == id {
+			ids = append(ids[:i], ids[i+1:]...)
+			break
+		}
+	}
+
+	if len(ids) == 0 {
+		delete(s.waiting, after)
+	} else {
+		s.waiting[after] = ids
+	}
+}
+
+// follow arms or cancels the events waiting for id, which just left the store
+// with outcome. It must be called with s.mu held.
+func (s *Simple[ID, E]) follow(id ID, outcome Outcome) {
+	ids, ok := s.waiting[id]
+	if !ok {
+		return
+	}
+	delete(s.waiting, id)
+
+	now := time.Now()
+	for _, w := range ids {
+		dep := s.pending[w]
+		delete(s.pending, w)
+		if outcome == OutcomeExpired || s.opts.dependentMode == DependentKeep {
+			// An event the store does not admit is dropped.
+			_, _ = s.add(w, dep.d.event, now.Add(dep.delay), dep.d.atExpire)
+		} else {
+			s.follow(w, OutcomeCancelled)
+		}
+	}
+}
+
+// cancelWaiting cancels the waiting event id, if any, and returns its data.
+// It must be called with s.mu held.
+func (s *Simple[ID, E]) cancelWaiting(id ID) *data[E] {
+	dep, ok := s.pending[id]
+	if !ok {
+		return nil
+	}
+
+	delete(s.pending, id)
+	s.unwait(id, dep.after)
+	s.follow(id, OutcomeCancelled)
+	return dep.d
+}
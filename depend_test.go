@@ -0,0 +1,34 @@
+package timerstore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStartAfterEventCancelValidate(t *testing.T) {
+	s := NewSimple[string, testEvent]()
+	defer s.Close()
+
+	at := time.Now().Add(time.Hour)
+	if err := s.Start("a", testEvent{At: at}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	for _, step := range [][2]string{{"b", "a"}, {"c", "b"}, {"d", "a"}} {
+		if err := s.StartAfterEvent(step[0], step[1], time.Hour, testEvent{At: at}, func() {}); err != nil {
+			t.Fatalf("StartAfterEvent(%q, %q): %v", step[0], step[1], err)
+		}
+	}
+
+	if _, ok := s.Cancel("d"); !ok {
+		t.Error("Cancel(d) found no waiting event")
+	}
+	if _, ok := s.Cancel("a"); !ok {
+		t.Error("Cancel(a) found no event")
+	}
+	if err := s.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+	if st := s.Stats(); st.Started != 1 || st.Cancelled != 1 {
+		t.Errorf("Stats = %+v; want 1 started and 1 cancelled", st)
+	}
+}
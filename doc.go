@@ -0,0 +1,29 @@
+// Package timerstore stores events that expire at a point in time and calls
+// back when they do. Simple keeps the events in memory and arms one runtime
+// timer per event; Persistent also stores them in a DB so that Recover can
+// re-arm them after a restart.
+//
+// # Testing
+//
+// The stores read the clock with time.Now and arm timers with time.AfterFunc
+// only; they take no clock to inject. This is what testing/synctest, from Go
+// 1.25 on, needs: a store created inside a synctest bubble runs on the
+// bubble's fake clock, so tests can let hours pass without sleeping:
+//
+//	synctest.Test(t, func(t *testing.T) {
+//		s := timerstore.NewSimple[string, event]()
+//		defer s.Close()
+//
+//		var fired atomic.Bool
+//		s.Start("a", event{at: time.Now().Add(time.Hour)}, func() { fired.Store(true) })
+//		time.Sleep(time.Hour) // returns at once
+//		synctest.Wait()       // until the callback has run
+//		if !fired.Load() {
+//			t.Error("event did not fire")
+//		}
+//	})
+//
+// The store must be created inside the bubble, since the goroutines it starts,
+// such as the worker pool of DispatchPool or the flusher of
+// WithDeferredDelete, belong to the bubble they are started in.
+package timerstore
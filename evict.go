@@ -0,0 +1,164 @@
+package timerstore
+
+import (
+	"errors"
+	"time"
+)
+
+// errEvict is returned by admit when the store is at capacity but its evictor
+// may make room for the event. add then evicts events until it fits.
+var errEvict = errors.New("timerstore: evict to make room")
+
+// Candidate describes a stored event offered to an Evictor.
+type Candidate[ID comparable, E Event] struct {
+	ID       ID
+	Event    E
+	Started  time.Time // when the event was started
+	Deadline time.Time // when its timer is due
+}
+
+// Evictor chooses the event a store at capacity evicts to make room for an
+// incoming one (see Simple.SetEvictor).
+//
+// Victim is called with the store lock held, on the goroutine calling Start,
+// once for every event evicted. candidates calls yield for every stored event
+// other than the one being started and the pinned ones (see Pinner), in no
+// particular order, until yield returns false. Victim returns the id of the
+// event to evict, or false to evict nothing, in which case Start fails with
+// ErrCapacityExceeded; events evicted for the same Start before that stay
+// evicted. An id not offered as a candidate is treated as false.
+//
+// Victim must not call the store, and holding the lock it delays every other
+// operation on the store, so it must return quickly. Ranging over every
+// candidate is O(n) in the number of stored events, which the built-in
+// evictors accept; a policy meant for large stores should stop ranging as
+// soon as it has found a good enough victim.
+type Evictor[ID comparable, E Event] interface {
+	Victim(candidates func(yield func(c Candidate[ID, E]) bool)) (ID, bool)
+}
+
+// Pinner is implemented by events that may be pinned, so that no Evictor ever
+// evicts them, such as critical events in a store shared with less important
+// ones. An incoming event that can only fit by evicting a pinned one is
+// rejected with ErrCapacityExceeded, without evicting anything. Pinned events
+// still count towards the capacity, so pinned events alone taking up the
+// whole capacity make every Start fail until some of them leave the store.
+// Pinned must not change while the event is stored.
+type Pinner interface {
+	Pinned() bool
+}
+
+func pinnedOf[E Event](event E) bool {
+	p, ok := any(event).(Pinner)
+	return ok && p.Pinned()
+}
+
+// SetEvictor makes the store, at the capacity set with WithCapacity, evict
+// events chosen by ev to make room for an incoming one, instead of failing
+// Start. Evicted events are removed as if cancelled: their callbacks are not
+// called, and Persistent deletes them from the DB. An event weighing more
+// than the whole capacity is rejected without evicting anything. Passing nil
+// makes Start fail again.
+func (s *Simple[ID, E]) SetEvictor(ev Evictor[ID, E]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictor = ev
+}
+
+// SetEvictor makes the store evict events chosen by ev to make room for an
+// incoming one, deleting them from the DB. See Simple.SetEvictor.
+func (p *Persistent[ID, E]) SetEvictor(ev Evictor[ID, E]) { p.s.SetEvictor(ev) }
+
+// PinnedCount returns the number of pinned events currently stored. See
+// Pinner.
+func (s *Simple[ID, E]) PinnedCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.pinned
+}
+
+// PinnedCount returns the number of pinned events stored in memory. See
+// Simple.PinnedCount.
+func (p *Persistent[ID, E]) PinnedCount() int { return p.s.PinnedCount() }
+
+// EvictNearestExpiry is an Evictor evicting the event due soonest, which would
+// have left the store first anyway.
+type EvictNearestExpiry[ID comparable, E Event] struct{}
+
+// Victim returns the candidate with the earliest deadline.
+func (EvictNearestExpiry[ID, E]) Victim(candidates func(yield func(c Candidate[ID, E]) bool)) (ID, bool) {
+	var best Candidate[ID, E]
+	found := false
+	candidates(func(c Candidate[ID, E]) bool {
+		if !found || c.Deadline.Before(best.Deadline) {
+			best, found = c, true
+		}
+		return true
+	})
+
+	return best.ID, found
+}
+
+// EvictOldest is an Evictor evicting the event started longest ago.
+type EvictOldest[ID comparable, E Event] struct{}
+
+// Victim returns the candidate started first.
+func (EvictOldest[ID, E]) Victim(candidates func(yield func(c Candidate[ID, E]) bool)) (ID, bool) {
+	var best Candidate[ID, E]
+	found := false
+	candidates(func(c Candidate[ID, E]) bool {
+		if !found || c.Started.Before(best.Started) {
+			best, found = c, true
+		}
+		return true
+	})
+
+	return best.ID, found
+}
+
+// evict makes room for event, to be started for id, by evicting the victims
+// chosen by the evictor until the store admits it. It returns the error of
+// the last admission check. It must be called with s.mu held.
+func (s *Simple[ID, E]) evict(id ID, event E, deadline time.Time) error {
+	if s.pinned > 0 {
+		pinned := weightOf(event)
+		for cid, d := range s.m {
+			if cid != id && d.pinned {
+				pinned += d.weight
+			}
+		}
+		if pinned > s.opts.capacity {
+			return ErrCapacityExceeded
+		}
+	}
+
+	candidates := func(yield func(c Candidate[ID, E]) bool) {
+		for cid, d := range s.m {
+			if cid == id || d.pinned {
+				continue
+			}
+			if !yield(Candidate[ID, E]{ID: cid, Event: d.event, Started: d.started, Deadline: d.deadline}) {
+				return
+			}
+		}
+	}
+
+	for {
+		victim, ok := s.evictor.Victim(candidates)
+		d, present := s.m[victim]
+		if !ok || !present || victim == id || d.pinned {
+			return ErrCapacityExceeded
+		}
+
+		s.cancel(victim, d)
+		if s.onEvict != nil {
+			s.onEvict(victim, d.event)
+		}
+
+		if err := s.admit(id, event, deadline); err != errEvict {
+			return err
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package timerstore
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSetEvictor(t *testing.T) {
+	s := NewSimple[string, testEvent](WithCapacity(2))
+	defer s.Close()
+
+	at := time.Now().Add(time.Hour)
+	s.Start("a", testEvent{At: at}, func() {})
+	s.Start("b", testEvent{At: at}, func() {})
+	if err := s.Start("c", testEvent{At: at}, func() {}); !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("Start without an evictor = %v; want ErrCapacityExceeded", err)
+	}
+
+	s.SetEvictor(EvictOldest[string, testEvent]{})
+	if err := s.Start("c", testEvent{At: at}, func() {}); err != nil {
+		t.Fatalf("Start with an evictor: %v", err)
+	}
+	if _, ok := s.Get("a"); ok {
+		t.Error("oldest event not evicted")
+	}
+	if s.Len() != 2 {
+		t.Errorf("Len = %d; want 2", s.Len())
+	}
+	if err := s.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package timerstore
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+const defaultErrorBuffer = 64
+
+// ExpiryError reports an error returned by the callback of an event started
+// with StartFallible.
+type ExpiryError[ID comparable] struct {
+	ID  ID
+	Err error
+}
+
+func (e ExpiryError[ID]) Error() string {
+	return fmt.Sprintf("timerstore: expiry of %v: %v", e.ID, e.Err)
+}
+
+func (e ExpiryError[ID]) Unwrap() error { return e.Err }
+
+// expiryErrors buffers the errors of StartFallible callbacks.
+type expiryErrors[ID comparable] struct {
+	once    sync.Once
+	ch      chan ExpiryError[ID]
+	dropped atomic.Uint64
+}
+
+// StartFallible is like Start, but for a callback returning an error. A
+// non-nil error is reported on the channel returned by Errors, along with id,
+// so that failing expiries can be supervised in one place, such as by a
+// goroutine of an errgroup.
+func (s *Simple[ID, E]) StartFallible(id ID, event E, atExpire func() error) error {
+	return s.Start(id, event, s.reporting(id, atExpire))
+}
+
+// reporting turns a callback returning an error into one reporting it.
+func (s *Simple[ID, E]) reporting(id ID, atExpire func() error) func() {
+	return func() {
+		if err := atExpire(); err != nil {
+			s.report(ExpiryError[ID]{ID: id, Err: err})
+		}
+	}
+}
+
+// Errors returns the channel on which the errors returned by the callbacks
+// of events started with StartFallible are reported. The channel buffers up
+// to the size set with WithErrorBuffer; while it is full, further errors are
+// dropped rather than blocking the callbacks, and counted by DroppedErrors.
+// Errors returned before Errors is first called are buffered too. The
+// channel is never closed.
+func (s *Simple[ID, E]) Errors() <-chan ExpiryError[ID] { return s.expiryErrors().ch }
+
+// DroppedErrors returns the number of errors of StartFallible callbacks dropped
+// because the channel returned by Errors was full.
+func (s *Simple[ID, E]) DroppedErrors() uint64 { return s.errs.dropped.Load() }
+
+func (s *Simple[ID, E]) expiryErrors() *expiryErrors[ID] {
+	s.errs.once.Do(func() {
+		n := s.opts.errorBuffer
+		if n <= 0 {
+			n = defaultErrorBuffer
+		}
+
+		s.errs.ch = make(chan ExpiryError[ID], n)
+	})
+
+	return &s.errs
+}
+
+func (s *Simple[ID, E]) report(err ExpiryError[ID]) {
+	errs := s.expiryErrors()
+	select {
+	case errs.ch <- err:
+	default:
+		errs.dropped.Add(1)
+	}
+}
+
+// StartFallible stores the event in the persistent storage and starts it in
+// memory with a callback returning an error, reported on Errors. See
+// Simple.StartFallible.
+func (p *Persistent[ID, E]) StartFallible(id ID, event E, atExpire func() error) error {
+	return p.Start(id, event, p.s.reporting(id, atExpire))
+}
+
+// Errors returns the channel on which the errors of StartFallible callbacks are
+// reported. See Simple.Errors.
+func (p *Persistent[ID, E]) Errors() <-chan ExpiryError[ID] { return p.s.Errors() }
+
+// DroppedErrors returns the number of errors of StartFallible callbacks
+// dropped. See Simple.DroppedErrors.
+func (p *Persistent[ID, E]) DroppedErrors() uint64 { return p.s.DroppedErrors() }
@@ -0,0 +1,64 @@
+package timerstore
+
+import "time"
+
+// StartWithGrace starts an event with two deadlines, as caches serving stale
+// entries while revalidating them need. At the event's ExpireAt it becomes
+// stale and onStale is called, but it stays in the store for grace longer;
+// then it is removed and atHardExpire is called like the callback of Start.
+// Both phases share one timer, so Cancel stops them both. Moving the deadline
+// before the event is stale moves the soft deadline; afterwards it moves the
+// hard one.
+//
+// onStale runs on the timer goroutine, bypassing the expiry rate limit and
+// the dispatch mode. A grace of zero or less makes StartWithGrace behave like
+// Start, never calling onStale.
+func (s *Simple[ID, E]) StartWithGrace(id ID, event E, grace time.Duration, onStale, atHardExpire func()) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return kept(s.addWithGrace(id, event, grace, onStale, atHardExpire))
+}
+
+// addWithGrace adds an event with a grace period. It must be called with s.mu
+// held.
+func (s *Simple[ID, E]) addWithGrace(id ID, event E, grace time.Duration, onStale, atHardExpire func()) error {
+	d, err := s.add(id, event, event.ExpireAt(), atHardExpire)
+	if err != nil {
+		return err
+	}
+	if grace > 0 {
+		d.grace, d.onStale = grace, onStale
+	}
+
+	return nil
+}
+
+// IsStale reports whether the event stored for id was started with
+// StartWithGrace and is past its soft deadline.
+func (s *Simple[ID, E]) IsStale(id ID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.m[id]
+	return ok && d.stale
+}
+
+// StartWithGrace stores the event in the persistent storage and starts it
+// with a grace period in memory. It is deleted from the DB at the hard
+// deadline. See Simple.StartWithGrace.
+func (p *Persistent[ID, E]) StartWithGrace(id ID, event E, grace time.Duration, onStale, atHardExpire func()) error {
+	if err := p.put(id, event); err != nil {
+		return kept(err)
+	}
+
+	p.s.mu.Lock()
+	err := p.s.addWithGrace(id, event, grace, onStale, atHardExpire)
+	p.s.mu.Unlock()
+
+	return p.rollback(id, event, kept(err))
+}
+
+// IsStale reports whether the event stored in memory for id is past its soft
+// deadline. See Simple.IsStale.
+func (p *Persistent[ID, E]) IsStale(id ID) bool { return p.s.IsStale(id) }
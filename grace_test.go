@@ -0,0 +1,44 @@
+package timerstore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStartWithGrace(t *testing.T) {
+	s := NewSimple[string, testEvent]()
+	defer s.Close()
+
+	stale, hard := make(chan struct{}), make(chan struct{})
+	err := s.StartWithGrace("a", testEvent{At: time.Now().Add(10 * time.Millisecond)}, 50*time.Millisecond,
+		func() { close(stale) }, func() { close(hard) })
+	if err != nil {
+		t.Fatalf("StartWithGrace: %v", err)
+	}
+	if s.IsStale("a") {
+		t.Error("event stale before its soft deadline")
+	}
+
+	select {
+	case <-stale:
+	case <-hard:
+		t.Fatal("atHardExpire called before onStale")
+	case <-time.After(time.Second):
+		t.Fatal("onStale not called")
+	}
+	if !s.IsStale("a") {
+		t.Error("event not stale after onStale")
+	}
+	if _, ok := s.Get("a"); !ok {
+		t.Error("stale event left the store before its grace ran out")
+	}
+
+	select {
+	case <-hard:
+	case <-time.After(time.Second):
+		t.Fatal("atHardExpire not called")
+	}
+	if _, ok := s.Get("a"); ok || s.IsStale("a") {
+		t.Error("event still stored after its hard deadline")
+	}
+}
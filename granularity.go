@@ -0,0 +1,71 @@
+package timerstore
+
+import "time"
+
+// adaptive tracks the start rate of a store for WithAdaptiveGranularity. It
+// is guarded by the store lock.
+type adaptive struct {
+	window time.Time // start of the current one-second window
+	count  int       // starts in the current window
+	avg    float64   // moving average of starts per second
+	coarse bool
+}
+
+// countStart records a Start at now and switches coarse mode on or off. It
+// must be called with s.mu held.
+func (s *Simple[ID, E]) countStart(now time.Time) {
+	if s.opts.coarseRate <= 0 || s.opts.coarse <= 0 {
+		return
+	}
+
+	a := &s.adaptive
+	if elapsed := now.Sub(a.window); elapsed >= time.Second {
+		a.avg = (a.avg + float64(a.count)) / 2
+		for i := time.Second; i < elapsed && a.avg > 0; i += time.Second {
+			// Windows without any Start.
+			a.avg /= 2
+		}
+		a.window, a.count = now, 0
+
+		threshold := float64(s.opts.coarseRate)
+		switch {
+		case !a.coarse && a.avg > threshold:
+			a.coarse = true
+		case a.coarse && a.avg < threshold/2:
+			a.coarse = false
+		}
+	}
+	a.count++
+}
+
+// coarsen rounds a timer wait up so that the timer fires on a boundary set
+// with WithDeadlineAlignment, if any, and on a multiple of the coarse
+// interval while the store is in coarse mode. It must be called with s.mu
+// held.
+func (s *Simple[ID, E]) coarsen(now time.Time, wait time.Duration) time.Duration {
+	if b := s.opts.alignBoundary; b > 0 {
+		// An event already due fires on the next boundary from now.
+		// Offsets are taken from the zero time with Truncate, as Sub between
+		// times far apart saturates.
+		wait = max(wait, 0)
+		at, epoch := now.Add(wait), s.opts.alignEpoch
+		offset := (at.Sub(at.Truncate(b)) - epoch.Sub(epoch.Truncate(b))) % b
+		if offset < 0 {
+			offset += b
+		}
+		if offset > 0 {
+			wait += b - offset
+		}
+	}
+
+	if !s.adaptive.coarse {
+		return wait
+	}
+
+	at := now.Add(wait)
+	if aligned := at.Truncate(s.opts.coarse); aligned.Before(at) {
+		at = aligned.Add(s.opts.coarse)
+	}
+
+	return at.Sub(now.Round(0))
+}
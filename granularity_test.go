@@ -0,0 +1,29 @@
+package timerstore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCoarsenAlignmentEpoch(t *testing.T) {
+	const b = 15 * time.Minute
+	now := time.Date(2024, 5, 6, 7, 8, 9, 10, time.UTC)
+	for _, epoch := range []time.Time{
+		{},
+		time.Date(1, 1, 1, 0, 7, 0, 0, time.UTC),
+		time.Date(9999, 1, 1, 0, 7, 0, 0, time.UTC),
+		time.Unix(0, 0).Add(7 * time.Minute),
+	} {
+		s := NewSimple[string, testEvent](WithDeadlineAlignment(b, epoch))
+		for _, wait := range []time.Duration{-time.Hour, 0, time.Second, b, 3 * b / 2} {
+			got := s.coarsen(now, wait)
+			at := now.Add(got)
+			if at.Minute()%15 != epoch.Minute()%15 || at.Second() != 0 || at.Nanosecond() != 0 {
+				t.Errorf("epoch %v, wait %v: fires at %v, not on a boundary", epoch, wait, at)
+			}
+			if got < max(wait, 0) || got >= max(wait, 0)+b {
+				t.Errorf("epoch %v, wait %v: coarsened to %v; want within one boundary", epoch, wait, got)
+			}
+		}
+	}
+}
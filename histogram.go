@@ -0,0 +1,86 @@
+package timerstore
+
+import (
+	"math"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// BucketCount is one bucket of a histogram: the number of observations no
+// greater than UpperBound and greater than the bound of the bucket before.
+// The last bucket has an UpperBound of math.MaxInt64.
+type BucketCount struct {
+	UpperBound time.Duration
+	Count      uint64
+}
+
+// histogram counts durations into fixed buckets without locking.
+type histogram struct {
+	bounds []time.Duration
+	counts []atomic.Uint64
+}
+
+func newHistogram(bounds []time.Duration) *histogram {
+	return &histogram{
+		bounds: append(bounds[:len(bounds):len(bounds)], math.MaxInt64),
+		counts: make([]atomic.Uint64, len(bounds)+1),
+	}
+}
+
+func (h *histogram) observe(d time.Duration) {
+	h.counts[sort.Search(len(h.bounds), func(i int) bool { return d <= h.bounds[i] })].Add(1)
+}
+
+func (h *histogram) buckets() []BucketCount {
+	b := make([]BucketCount, len(h.bounds))
+	for i := range b {
+		b[i] = BucketCount{UpperBound: h.bounds[i], Count: h.counts[i].Load()}
+	}
+
+	return b
+}
+
+// LifetimeHistogram returns how long the events removed so far lived, from
+// Start to their removal by expiry, cancellation or replacement, bucketed by
+// the bounds given to WithLifetimeHistogram. It returns nil if the store was
+// not configured with WithLifetimeHistogram.
+func (s *Simple[ID, E]) LifetimeHistogram() []BucketCount {
+	if s.lifetimes == nil {
+		return nil
+	}
+
+	return s.lifetimes.buckets()
+}
+
+// ExpiryDistribution counts the stored events by how far in the future they
+// are due. buckets are upper bounds relative to now, in increasing order: the
+// i-th count is the number of events due no later than buckets[i] from now
+// and after buckets[i-1]. Events already past due count in the first bucket.
+// The result has one more count than buckets, for the events due beyond the
+// largest bound. ExpiryDistribution holds the store lock for O(n) and changes
+// nothing.
+func (s *Simple[ID, E]) ExpiryDistribution(buckets []time.Duration) []int {
+	counts := make([]int, len(buckets)+1)
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, d := range s.m {
+		in := d.deadline.Sub(now)
+		counts[sort.Search(len(buckets), func(i int) bool { return in <= buckets[i] })]++
+	}
+
+	return counts
+}
+
+// ExpiryDistribution counts the events stored in memory by how far in the
+// future they are due. See Simple.ExpiryDistribution.
+func (p *Persistent[ID, E]) ExpiryDistribution(buckets []time.Duration) []int {
+	return p.s.ExpiryDistribution(buckets)
+}
+
+// LifetimeHistogram returns how long the events removed from memory lived.
+// See Simple.LifetimeHistogram.
+func (p *Persistent[ID, E]) LifetimeHistogram() []BucketCount { return p.s.LifetimeHistogram() }
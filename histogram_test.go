@@ -0,0 +1,67 @@
+package timerstore
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLifetimeHistogram(t *testing.T) {
+	const bound = 20 * time.Millisecond
+	s := NewSimple[string, testEvent](WithLifetimeHistogram(bound))
+	defer s.Close()
+
+	// "a" expires at once and counts in the first bucket, "b" is cancelled
+	// after the bound and counts in the last.
+	expired := make(chan struct{})
+	s.Start("b", testEvent{At: time.Now().Add(time.Hour)}, func() {})
+	s.Start("a", testEvent{At: time.Now()}, func() { close(expired) })
+	<-expired
+	time.Sleep(2 * bound)
+	s.Cancel("b")
+
+	want := []BucketCount{{UpperBound: bound, Count: 1}, {UpperBound: math.MaxInt64, Count: 1}}
+	if got := s.LifetimeHistogram(); !slices.Equal(got, want) {
+		t.Errorf("LifetimeHistogram = %v; want %v", got, want)
+	}
+	if got := NewSimple[string, testEvent]().LifetimeHistogram(); got != nil {
+		t.Errorf("LifetimeHistogram without the option = %v; want nil", got)
+	}
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	h := newHistogram([]time.Duration{time.Second, time.Minute})
+	for _, d := range []time.Duration{0, time.Second, 2 * time.Second, time.Minute, time.Hour} {
+		h.observe(d)
+	}
+
+	want := []BucketCount{{time.Second, 2}, {time.Minute, 2}, {math.MaxInt64, 1}}
+	if got := h.buckets(); !slices.Equal(got, want) {
+		t.Errorf("buckets = %v; want %v", got, want)
+	}
+}
+
+func TestExpiryDistribution(t *testing.T) {
+	s := NewSimple[string, testEvent]()
+	defer s.Close()
+
+	now := time.Now()
+	for id, in := range map[string]time.Duration{
+		"soon": time.Minute,
+		"hour": 30 * time.Minute,
+		"day":  2 * time.Hour,
+		"late": 48 * time.Hour,
+	} {
+		s.Start(id, testEvent{At: now.Add(in)}, func() {})
+	}
+	// A suspended event stays in the store past its deadline.
+	s.Start("past", testEvent{At: now.Add(time.Hour)}, func() {})
+	s.Suspend("past")
+	s.Reschedule("past", now.Add(-time.Minute))
+
+	got := s.ExpiryDistribution([]time.Duration{time.Minute + time.Second, time.Hour, 24 * time.Hour})
+	if want := []int{2, 1, 1, 1}; !slices.Equal(got, want) {
+		t.Errorf("ExpiryDistribution = %v; want %v", got, want)
+	}
+}
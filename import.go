@@ -0,0 +1,63 @@
+package timerstore
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+)
+
+// ImportStream reads records from r one at a time and starts an event for
+// each, for migrating a dataset too large to hold in memory at once. Every
+// record is a uvarint length followed by that many bytes, which decode turns
+// into an id and an event. atExpire, if not nil, is called with the id of
+// each imported event when it expires; otherwise the expiry goes to the
+// handler set with SetExpiryHandler. Events already past due fire as soon as
+// they are imported.
+//
+// ImportStream stops at the end of r, at the first error, or when ctx is
+// done, checked before every record, and returns the number of events
+// started so far, which is also valid along with an error. Events kept by the
+// replace mode count as imported.
+func (s *Simple[ID, E]) ImportStream(ctx context.Context, r io.Reader, decode func([]byte) (ID, E, error), atExpire func(id ID)) (int, error) {
+	return importStream(ctx, r, decode, atExpire, s.Start)
+}
+
+// importStream imports the records of r with start.
+func importStream[ID comparable, E Event](ctx context.Context, r io.Reader, decode func([]byte) (ID, E, error), atExpire func(id ID), start func(id ID, event E, atExpire func()) error) (int, error) {
+	br := bufio.NewReader(r)
+	n := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+
+		record, err := readChunk(br, "import record")
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, fmt.Errorf("timerstore: read record %d: %w", n, truncated("import record", err))
+		}
+
+		id, event, err := decode(record)
+		if err != nil {
+			return n, fmt.Errorf("timerstore: decode record %d: %w", n, err)
+		}
+
+		var fn func()
+		if atExpire != nil {
+			fn = func() { atExpire(id) }
+		}
+		if err := start(id, event, fn); err != nil {
+			return n, fmt.Errorf("timerstore: start %v: %w", id, err)
+		}
+		n++
+	}
+}
+
+// ImportStream reads records from r one at a time and starts an event for
+// each, storing it in the DB too. See Simple.ImportStream.
+func (p *Persistent[ID, E]) ImportStream(ctx context.Context, r io.Reader, decode func([]byte) (ID, E, error), atExpire func(id ID)) (int, error) {
+	return importStream(ctx, r, decode, atExpire, p.Start)
+}
@@ -0,0 +1,69 @@
+package timerstore
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// StringKeyCodec is a KeyCodec for string ids, encoded as their bytes.
+type StringKeyCodec[ID ~string] struct{}
+
+var _ KeyCodec[string] = StringKeyCodec[string]{}
+
+// EncodeKey returns the bytes of id.
+func (StringKeyCodec[ID]) EncodeKey(id ID) ([]byte, error) { return []byte(id), nil }
+
+// DecodeKey returns data as an id.
+func (StringKeyCodec[ID]) DecodeKey(data []byte) (ID, error) { return ID(data), nil }
+
+// Integer is the set of integer types an IntKeyCodec encodes.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// IntKeyCodec is a KeyCodec for integer ids, encoded in decimal so that they
+// also read well as text keys, such as string primary keys in SQL.
+type IntKeyCodec[ID Integer] struct{}
+
+var _ KeyCodec[int64] = IntKeyCodec[int64]{}
+
+// EncodeKey returns id in decimal.
+func (IntKeyCodec[ID]) EncodeKey(id ID) ([]byte, error) {
+	if signed[ID]() {
+		return strconv.AppendInt(nil, int64(id), 10), nil
+	}
+
+	return strconv.AppendUint(nil, uint64(id), 10), nil
+}
+
+// DecodeKey parses the decimal id in data. It fails if data is not a number
+// or does not fit ID.
+func (IntKeyCodec[ID]) DecodeKey(data []byte) (ID, error) {
+	if signed[ID]() {
+		v, err := strconv.ParseInt(string(data), 10, 64)
+		if err == nil && int64(ID(v)) != v {
+			err = strconv.ErrRange
+		}
+		if err != nil {
+			return 0, fmt.Errorf("timerstore: decode key %q: %w", data, err)
+		}
+
+		return ID(v), nil
+	}
+
+	v, err := strconv.ParseUint(string(data), 10, 64)
+	if err == nil && uint64(ID(v)) != v {
+		err = strconv.ErrRange
+	}
+	if err != nil {
+		return 0, fmt.Errorf("timerstore: decode key %q: %w", data, err)
+	}
+
+	return ID(v), nil
+}
+
+func signed[ID Integer]() bool {
+	var zero ID
+	return zero-1 < zero
+}
@@ -0,0 +1,85 @@
+package timerstore
+
+import "time"
+
+// startLazy starts an event in memory only and arms a timer to put it to the
+// DB once it is due within the lazy persist window.
+func (p *Persistent[ID, E]) startLazy(id ID, event E, atExpire func()) error {
+	p.s.mu.Lock()
+	prev, replaced := p.s.m[id]
+	d, err := p.s.add(id, event, event.ExpireAt(), atExpire)
+	if err != nil {
+		p.s.mu.Unlock()
+		return kept(err)
+	}
+	d.promote = time.AfterFunc(time.Until(event.ExpireAt().Add(-p.opts.lazyWindow)), func() { p.promote(id, d) })
+	p.s.mu.Unlock()
+
+	if replaced && prev.promote == nil {
+		// The replaced event was in the DB and would be recovered in place
+		// of this one.
+		p.delete(id, prev.event, ReasonCancelled)
+	}
+
+	return nil
+}
+
+// promote puts the lazily persisted event d of id to the DB, unless it is no
+// longer stored. The put is made without holding the store lock; if d left
+// the store meanwhile, the put is undone.
+func (p *Persistent[ID, E]) promote(id ID, d *data[E]) {
+	p.s.mu.Lock()
+	if p.s.m[id] != d {
+		p.s.mu.Unlock()
+		return
+	}
+	event := d.event
+	p.s.mu.Unlock()
+
+	err := p.db.Put(id, event)
+
+	p.s.mu.Lock()
+	cur, ok := p.s.m[id]
+	if cur == d {
+		if err == nil {
+			d.promote = nil
+		}
+		p.s.mu.Unlock()
+	} else {
+		// d expired, was cancelled or replaced during the put, and its
+		// removal did not delete it from the DB, where it was not yet.
+		reason := ReasonCancelled
+		if d.outcome == OutcomeExpired {
+			reason = ReasonExpired
+		}
+		persisted := ok && cur.promote == nil
+		var replacement E
+		if persisted {
+			replacement = cur.event
+		}
+		p.s.mu.Unlock()
+
+		if err == nil && persisted {
+			// The put overwrote the event that replaced d in the DB.
+			err = p.db.Put(id, replacement)
+		} else if err == nil {
+			p.delete(id, event, reason)
+		}
+	}
+
+	if fn := p.onPromote.Load(); fn != nil {
+		(*fn)(id, event, err)
+	}
+}
+
+// OnPromote registers fn to be called every time an event started lazily,
+// see WithLazyPersist, is put to the DB, with the error of the put. An event
+// which fails to be put stays in memory only. Passing nil removes the hook.
+func (p *Persistent[ID, E]) OnPromote(fn func(id ID, event E, err error)) {
+	if fn == nil {
+		p.onPromote.Store(nil)
+		return
+	}
+
+	p.onPromote.Store(&fn)
+}
@@ -0,0 +1,74 @@
+package timerstore
+
+import (
+	"testing"
+	"time"
+)
+
+// blockingDB is a mapDB whose Put waits for release once entered is
+// signalled.
+type blockingDB struct {
+	*mapDB[testEvent]
+	entered, release chan struct{}
+}
+
+func (db *blockingDB) Put(id string, event testEvent) error {
+	db.entered <- struct{}{}
+	<-db.release
+	return db.mapDB.Put(id, event)
+}
+
+func TestPromoteCancelledDuringPut(t *testing.T) {
+	db := &blockingDB{newMapDB[testEvent](), make(chan struct{}), make(chan struct{})}
+	p := NewPersistentStore[string, testEvent](db, WithLazyPersist(150*time.Millisecond))
+	defer p.Close()
+
+	promoted := make(chan error, 1)
+	p.OnPromote(func(_ string, _ testEvent, err error) { promoted <- err })
+	if err := p.Start("a", testEvent{At: time.Now().Add(200 * time.Millisecond)}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	<-db.entered
+	// The store lock is not held during the put.
+	if _, ok := p.Cancel("a"); !ok {
+		t.Fatal("Cancel found no event")
+	}
+	close(db.release)
+
+	if err := <-promoted; err != nil {
+		t.Fatalf("promotion failed: %v", err)
+	}
+	if db.has("a") {
+		t.Error("event cancelled during its promotion left in the DB")
+	}
+}
+
+func TestPromoteRescheduled(t *testing.T) {
+	db := newMapDB[testEvent]()
+	p := NewPersistentStore[string, testEvent](db, WithLazyPersist(150*time.Millisecond))
+	defer p.Close()
+
+	promoted := make(chan error, 1)
+	p.OnPromote(func(_ string, _ testEvent, err error) { promoted <- err })
+	if err := p.Start("a", testEvent{At: time.Now().Add(time.Hour)}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	// Bringing the deadline into the window promotes the event right away.
+	if _, ok := p.Reschedule("a", time.Now().Add(100*time.Millisecond)); !ok {
+		t.Fatal("Reschedule found no event")
+	}
+
+	select {
+	case err := <-promoted:
+		if err != nil {
+			t.Fatalf("promotion failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("rescheduled event not promoted")
+	}
+	if !db.has("a") {
+		t.Error("promoted event not in the DB")
+	}
+}
@@ -0,0 +1,106 @@
+package timerstore
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// limiter is a token bucket releasing callbacks at a fixed rate. Callbacks
+// that cannot run immediately are queued in deadline order.
+type limiter struct {
+	rate float64      // tokens per second, also the bucket size
+	run  func(func()) // runs a callback released from the queue
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	queue  callbackQueue
+	timer  *time.Timer
+}
+
+func newLimiter(perSecond int, run func(func())) *limiter {
+	return &limiter{rate: float64(perSecond), run: run, tokens: float64(perSecond), last: time.Now()}
+}
+
+// refill adds the tokens accumulated since the last refill. It must be called
+// with l.mu held.
+func (l *limiter) refill() {
+	now := time.Now()
+	l.tokens = min(l.rate, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+}
+
+// submit runs fn right away if a token is available and nothing is queued
+// ahead of it, and queues it otherwise.
+func (l *limiter) submit(deadline time.Time, fn func()) {
+	l.mu.Lock()
+	l.refill()
+	if len(l.queue) == 0 && l.tokens >= 1 {
+		l.tokens--
+		l.mu.Unlock()
+		fn()
+		return
+	}
+
+	heap.Push(&l.queue, queuedCallback{deadline: deadline, fn: fn})
+	l.schedule()
+	l.mu.Unlock()
+}
+
+// schedule arms the drain timer for when the next token becomes available. It
+// must be called with l.mu held.
+func (l *limiter) schedule() {
+	if l.timer != nil || len(l.queue) == 0 {
+		return
+	}
+
+	wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	l.timer = time.AfterFunc(wait, l.drain)
+}
+
+// drain releases as many queued callbacks as there are tokens, in deadline
+// order, and re-arms itself while callbacks remain queued.
+func (l *limiter) drain() {
+	l.mu.Lock()
+	l.timer = nil
+	l.refill()
+	var released []func()
+	for len(l.queue) > 0 && l.tokens >= 1 {
+		l.tokens--
+		released = append(released, heap.Pop(&l.queue).(queuedCallback).fn)
+	}
+	l.schedule()
+	l.mu.Unlock()
+
+	for _, fn := range released {
+		l.run(fn)
+	}
+}
+
+func (l *limiter) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.queue)
+}
+
+type queuedCallback struct {
+	deadline time.Time
+	fn       func()
+}
+
+// callbackQueue is a min-heap of callbacks ordered by deadline.
+type callbackQueue []queuedCallback
+
+func (q callbackQueue) Len() int           { return len(q) }
+func (q callbackQueue) Less(i, j int) bool { return q[i].deadline.Before(q[j].deadline) }
+func (q callbackQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *callbackQueue) Push(x any)        { *q = append(*q, x.(queuedCallback)) }
+
+func (q *callbackQueue) Pop() any {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,43 @@
+package timerstore
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLimiterDeadlineOrder(t *testing.T) {
+	var mu sync.Mutex
+	var order []int
+	done := make(chan struct{})
+	l := newLimiter(10, func(fn func()) { fn() })
+	l.tokens = 0
+
+	now := time.Now()
+	for _, i := range []int{3, 1, 2} {
+		l.submit(now.Add(time.Duration(i)*time.Second), func() {
+			mu.Lock()
+			defer mu.Unlock()
+
+			order = append(order, i)
+			if len(order) == 3 {
+				close(done)
+			}
+		})
+	}
+	if n := l.len(); n != 3 {
+		t.Fatalf("%d callbacks queued; want 3", n)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("queued callbacks not released")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []int{1, 2, 3}; !slices.Equal(order, want) {
+		t.Errorf("callbacks ran in order %v; want %v", order, want)
+	}
+}
@@ -0,0 +1,68 @@
+package timerstore
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Load returns the event for id, loading it from the DB if it is not stored
+// in memory and arming its timer, for a store whose DB holds more events than
+// memory does. The DB must be a Getter. An event loaded already past due
+// fires at once, like one recovered by Recover, and goes to the default
+// handler since its callback is not persisted. ok is false with a nil error
+// if neither memory nor the DB has an event for id; an error is returned if
+// the DB lookup failed, or if the store does not admit the event.
+//
+// A miss costs one DB lookup, made without holding the store lock. An event
+// started for id meanwhile wins over the loaded one, which is then dropped,
+// but a Load racing with Cancel for the same id may arm the event Cancel
+// deleted. See WithReadThrough for making Get and Cancel load on a miss.
+func (p *Persistent[ID, E]) Load(id ID) (E, bool, error) {
+	if event, ok := p.s.peek(id); ok {
+		return event, true, nil
+	}
+
+	var zeroE E
+	event, ok, err := p.lookup(id)
+	if !ok {
+		return zeroE, false, err
+	}
+
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	if d, stored := p.s.m[id]; stored {
+		return d.event, true, nil
+	}
+
+	at := event.ExpireAt()
+	d, err := p.s.add(id, event, at, nil)
+	if err == errKeep {
+		return zeroE, false, nil
+	}
+	if err != nil {
+		return zeroE, false, err
+	}
+	d.recovered = !at.After(d.started)
+
+	return event, true, nil
+}
+
+// lookup gets the event for id from the DB. ok is false with a nil error if
+// the DB has none.
+func (p *Persistent[ID, E]) lookup(id ID) (event E, ok bool, err error) {
+	g, isGetter := p.db.(Getter[ID, E])
+	if !isGetter {
+		return event, false, errNotImplemented("Getter")
+	}
+
+	event, err = g.Get(id)
+	if errors.Is(err, ErrNotFound) {
+		return event, false, nil
+	}
+	if err != nil {
+		return event, false, fmt.Errorf("timerstore: load %v: %w", id, err)
+	}
+
+	return event, true, nil
+}
@@ -0,0 +1,67 @@
+package timerstore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadThrough(t *testing.T) {
+	db := newMapDB[testEvent]()
+	p := NewPersistentStore[string, testEvent](db, WithReadThrough())
+	defer p.Close()
+
+	at := time.Now().Add(time.Hour)
+	for _, id := range []string{"get", "cancel", "reschedule"} {
+		db.Put(id, testEvent{At: at, Name: id})
+	}
+
+	if got, ok := p.Get("get"); !ok || got.Name != "get" {
+		t.Errorf("Get = %v, %v; want the event from the DB", got, ok)
+	}
+	if p.Len() != 1 {
+		t.Errorf("Len after Get = %d; want the loaded event armed", p.Len())
+	}
+
+	if _, ok := p.Reschedule("reschedule", at.Add(time.Hour)); !ok {
+		t.Error("Reschedule found no event")
+	}
+	if p.Len() != 2 {
+		t.Errorf("Len after Reschedule = %d; want the loaded event armed", p.Len())
+	}
+	if got, _ := p.NthExpiry(2); !got.Equal(at.Add(time.Hour)) {
+		t.Errorf("rescheduled deadline = %v; want %v", got, at.Add(time.Hour))
+	}
+
+	if _, ok := p.Cancel("cancel"); !ok {
+		t.Error("Cancel found no event")
+	}
+	if p.Len() != 2 || db.has("cancel") {
+		t.Error("Cancel armed the event or left it in the DB")
+	}
+
+	if _, ok := p.Get("none"); ok {
+		t.Error("Get found an event in neither memory nor the DB")
+	}
+}
+
+func TestLoadPastDue(t *testing.T) {
+	db := newMapDB[testEvent]()
+	p := NewPersistentStore[string, testEvent](db)
+	defer p.Close()
+
+	fired := make(chan string, 1)
+	p.SetDefaultHandler(func(id string, _ testEvent) { fired <- id })
+	db.Put("a", testEvent{At: time.Now().Add(-time.Minute)})
+
+	if _, ok, err := p.Load("a"); !ok || err != nil {
+		t.Fatalf("Load = %v, %v; want the event", ok, err)
+	}
+	select {
+	case id := <-fired:
+		if id != "a" {
+			t.Errorf("default handler got %q; want a", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("loaded past-due event did not fire")
+	}
+}
@@ -0,0 +1,58 @@
+package timerstore
+
+import (
+	"time"
+	"unsafe"
+)
+
+// Sizer is implemented by events that can estimate their own memory
+// footprint, including anything they reference. It is used by
+// ApproxMemoryBytes.
+type Sizer interface {
+	SizeBytes() int64
+}
+
+const (
+	// timerOverheadBytes approximates what the runtime keeps for an armed
+	// timer beyond the time.Timer value, plus the closure it calls.
+	timerOverheadBytes = 96
+	// mapOverheadBytes approximates the per-entry overhead of a Go map
+	// beyond the key and value.
+	mapOverheadBytes = 16
+	// eventFallbackBytes is the payload estimate for events that do not
+	// implement Sizer, on top of the size of the event value itself.
+	eventFallbackBytes = 64
+)
+
+// ApproxMemoryBytes estimates the memory used by the events stored. Each event
+// is counted as the size of the internal entry, its timer and its map slot,
+// plus its payload: the result of SizeBytes for events implementing Sizer,
+// and a fixed estimate otherwise.
+//
+// This is a heuristic meant for sizing capacity limits, not an exact figure:
+// it ignores allocator rounding, memory held by callbacks and anything shared
+// between events, and the runtime overheads are rough constants. Use
+// runtime.MemStats for authoritative numbers.
+func (s *Simple[ID, E]) ApproxMemoryBytes() int64 {
+	var zeroID ID
+	perEntry := int64(unsafe.Sizeof(data[E]{})+unsafe.Sizeof(time.Timer{})+unsafe.Sizeof(zeroID)+unsafe.Sizeof(&data[E]{})) +
+		timerOverheadBytes + mapOverheadBytes
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total := int64(len(s.m)) * perEntry
+	for _, d := range s.m {
+		if sz, ok := any(d.event).(Sizer); ok {
+			total += sz.SizeBytes()
+		} else {
+			total += eventFallbackBytes
+		}
+	}
+
+	return total
+}
+
+// ApproxMemoryBytes estimates the memory used by the events stored in memory.
+// See Simple.ApproxMemoryBytes.
+func (p *Persistent[ID, E]) ApproxMemoryBytes() int64 { return p.s.ApproxMemoryBytes() }
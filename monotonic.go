@@ -0,0 +1,40 @@
+package timerstore
+
+import "time"
+
+// StartAt is like Start, but arms the timer to fire fireIn from now rather
+// than at the event's ExpireAt, for deadlines kept as durations on a
+// monotonic clock shared with another subsystem. The event is still stored,
+// but its ExpireAt is not used for the timer, so no wall-clock conversion is
+// made and a skew between the clocks of the subsystems has no effect. A zero
+// or negative fireIn expires the event right away.
+//
+// The schedule, not the event, then tells when the event is due: Get returns
+// the event as given, whose ExpireAt may not match when it actually expires,
+// so any time left computed from it is only approximate. Replacing the event,
+// as with Replace, arms the timer by the ExpireAt of the new event again.
+// Bounds set with WithTTLBounds apply to the schedule. The event does not
+// coalesce with others.
+func (s *Simple[ID, E]) StartAt(id ID, event E, fireIn time.Duration, atExpire func()) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.add(id, event, time.Now().Add(fireIn), atExpire)
+	return kept(err)
+}
+
+// StartAt stores the event in the persistent storage and starts it in memory
+// to expire fireIn from now. The DB only holds the event, so an event
+// recovered after a restart is scheduled by its ExpireAt again. See
+// Simple.StartAt.
+func (p *Persistent[ID, E]) StartAt(id ID, event E, fireIn time.Duration, atExpire func()) error {
+	if err := p.put(id, event); err != nil {
+		return kept(err)
+	}
+
+	p.s.mu.Lock()
+	_, err := p.s.add(id, event, time.Now().Add(fireIn), atExpire)
+	p.s.mu.Unlock()
+
+	return p.rollback(id, event, kept(err))
+}
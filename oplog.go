@@ -0,0 +1,232 @@
+package timerstore
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// oplogHeader is the first line of an operation log. The number is the
+// format version.
+const oplogHeader = "timerstore oplog 1"
+
+// ErrOpLogFormat is returned by ReplayOperations for input that is not an
+// operation log in a format it understands.
+var ErrOpLogFormat = errors.New("timerstore: not an operation log")
+
+// Operation names used in an operation log.
+const (
+	OpStart      = "start"
+	OpCancel     = "cancel"
+	OpReschedule = "reschedule"
+)
+
+// opRecord is one line of an operation log.
+type opRecord struct {
+	Time     time.Time  `json:"time"`
+	Op       string     `json:"op"`
+	ID       []byte     `json:"id"`
+	ExpireAt *time.Time `json:"expireAt,omitempty"`
+	Event    []byte     `json:"event,omitempty"`
+}
+
+// Rescheduler is implemented by stores whose events can be moved to another
+// deadline, such as Simple.
+type Rescheduler[ID comparable, E Event] interface {
+	Reschedule(id ID, at time.Time) (E, bool)
+}
+
+// OperationLog is a Store that forwards to another one and records every
+// successful Start, Cancel and Reschedule to a writer, so that the operations
+// can be replayed later with ReplayOperations. Create one with
+// WithOperationLog.
+//
+// The log is text: a header line naming the format version, then one JSON
+// object per operation with the time it happened, the operation, the id and,
+// for Start and Reschedule, the new ExpireAt. Start records the encoded event
+// too. Operations racing on different goroutines are recorded in the order
+// they finish, which may differ from the order the store applied them.
+type OperationLog[ID comparable, E Event] struct {
+	s      Store[ID, E]
+	keys   KeyCodec[ID]
+	events Codec[E]
+
+	mu  sync.Mutex
+	w   *bufio.Writer
+	err error
+}
+
+var _ Store[any, Event] = &OperationLog[any, Event]{}
+
+// WithOperationLog wraps s so that its mutating operations are recorded to w,
+// with ids encoded by keys and events by events. The header is written at
+// once, and every operation is flushed to w as it is recorded.
+func WithOperationLog[ID comparable, E Event](s Store[ID, E], w io.Writer, keys KeyCodec[ID], events Codec[E]) *OperationLog[ID, E] {
+	l := &OperationLog[ID, E]{s: s, keys: keys, events: events, w: bufio.NewWriter(w)}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.w.WriteString(oplogHeader + "\n"); err != nil {
+		l.err = err
+	} else {
+		l.err = l.w.Flush()
+	}
+
+	return l
+}
+
+// Start starts the event in the wrapped store and records it.
+func (l *OperationLog[ID, E]) Start(id ID, event E, atExpire func()) error {
+	if err := l.s.Start(id, event, atExpire); err != nil {
+		return err
+	}
+
+	at := event.ExpireAt()
+	payload, err := l.events.Encode(event)
+	if err != nil {
+		l.fail(fmt.Errorf("timerstore: encode event: %w", err))
+		return nil
+	}
+
+	l.record(OpStart, id, &at, payload)
+	return nil
+}
+
+// Cancel cancels the event in the wrapped store and records it if it was
+// present.
+func (l *OperationLog[ID, E]) Cancel(id ID) (E, bool) {
+	event, ok := l.s.Cancel(id)
+	if ok {
+		l.record(OpCancel, id, nil, nil)
+	}
+
+	return event, ok
+}
+
+// Reschedule moves the deadline of the event in the wrapped store and records
+// it if it was present. The wrapped store must be a Rescheduler; if it is
+// not, Reschedule reports the event absent.
+func (l *OperationLog[ID, E]) Reschedule(id ID, at time.Time) (E, bool) {
+	r, ok := l.s.(Rescheduler[ID, E])
+	if !ok {
+		var zeroE E
+		return zeroE, false
+	}
+
+	event, ok := r.Reschedule(id, at)
+	if ok {
+		l.record(OpReschedule, id, &at, nil)
+	}
+
+	return event, ok
+}
+
+// Err returns the first error met while recording, if any. Recording stops
+// after an error, but the operations still go through to the wrapped store.
+func (l *OperationLog[ID, E]) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.err
+}
+
+func (l *OperationLog[ID, E]) record(op string, id ID, at *time.Time, payload []byte) {
+	key, err := l.keys.EncodeKey(id)
+	if err != nil {
+		l.fail(fmt.Errorf("timerstore: encode key: %w", err))
+		return
+	}
+
+	line, err := json.Marshal(opRecord{Time: time.Now(), Op: op, ID: key, ExpireAt: at, Event: payload})
+	if err != nil {
+		l.fail(err)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.err != nil {
+		return
+	}
+
+	l.w.Write(line)
+	l.w.WriteByte('\n')
+	l.err = l.w.Flush()
+}
+
+func (l *OperationLog[ID, E]) fail(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.err == nil {
+		l.err = err
+	}
+}
+
+// ReplayOperations reads an operation log written by an OperationLog from r
+// and applies its operations to s, in order and at once, ignoring the times
+// they were recorded at. Started events get the callback returned by
+// atExpire, which may be nil, for their id and event. Reschedule operations
+// need s to be a Rescheduler. ReplayOperations returns the number of
+// operations applied, also when it fails partway.
+func ReplayOperations[ID comparable, E Event](r io.Reader, s Store[ID, E], keys KeyCodec[ID], events Codec[E], atExpire func(id ID, event E) func()) (int, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 16<<20)
+	if !sc.Scan() || sc.Text() != oplogHeader {
+		if err := sc.Err(); err != nil {
+			return 0, err
+		}
+
+		return 0, ErrOpLogFormat
+	}
+
+	n := 0
+	for sc.Scan() {
+		var rec opRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return n, fmt.Errorf("%w: line %d: %v", ErrOpLogFormat, n+2, err)
+		}
+
+		id, err := keys.DecodeKey(rec.ID)
+		if err != nil {
+			return n, fmt.Errorf("timerstore: decode key: %w", err)
+		}
+
+		switch rec.Op {
+		case OpStart:
+			event, err := events.Decode(rec.Event)
+			if err != nil {
+				return n, fmt.Errorf("timerstore: decode event: %w", err)
+			}
+
+			var fn func()
+			if atExpire != nil {
+				fn = atExpire(id, event)
+			}
+			if err := s.Start(id, event, fn); err != nil {
+				return n, err
+			}
+		case OpCancel:
+			s.Cancel(id)
+		case OpReschedule:
+			r, ok := s.(Rescheduler[ID, E])
+			if !ok {
+				return n, errors.New("timerstore: store does not implement Rescheduler")
+			}
+			if rec.ExpireAt == nil {
+				return n, fmt.Errorf("%w: line %d: reschedule without expireAt", ErrOpLogFormat, n+2)
+			}
+			r.Reschedule(id, *rec.ExpireAt)
+		default:
+			return n, fmt.Errorf("%w: line %d: unknown operation %q", ErrOpLogFormat, n+2, rec.Op)
+		}
+		n++
+	}
+
+	return n, sc.Err()
+}
@@ -0,0 +1,337 @@
+package timerstore
+
+import "time"
+
+const (
+	defaultRedeliveryBase = time.Second
+	defaultRedeliveryMax  = time.Minute
+	defaultAckTimeout     = time.Minute
+	defaultMaxUnacked     = 100
+)
+
+// Option configures optional behaviour of a store.
+type Option func(*options)
+
+type options struct {
+	redeliveryBase  time.Duration
+	redeliveryMax   time.Duration
+	expiryRate      int
+	minRearm        time.Duration
+	deleteInterval  time.Duration
+	deleteBatch     int
+	capacity        int64
+	driftCheck      time.Duration
+	closeMode       CloseMode
+	replaceMode     ReplaceMode
+	dispatch        Dispatch
+	poolSize        int
+	ackTimeout      time.Duration
+	maxUnacked      int
+	lifetimes       []time.Duration
+	coarseRate      int
+	coarse          time.Duration
+	lazyWindow      time.Duration
+	resumeRate      int
+	ttlMin, ttlMax  time.Duration
+	ttlMode         TTLMode
+	panicPolicy     PanicPolicy
+	batchFlush      time.Duration
+	alignBoundary   time.Duration
+	alignEpoch      time.Time
+	errorBuffer     int
+	refreshTTL      time.Duration
+	dependentMode   DependentMode
+	callbackTimeout time.Duration
+	readThrough     bool
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		redeliveryBase: defaultRedeliveryBase,
+		redeliveryMax:  defaultRedeliveryMax,
+		ackTimeout:     defaultAckTimeout,
+		maxUnacked:     defaultMaxUnacked,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// WithRedeliveryBackoff sets the delay before an unacknowledged event started
+// with Persistent.StartReliable, or a rejected one started with Enqueue, is
+// delivered again. The first redelivery happens after base, and every
+// following one doubles the delay up to max.
+func WithRedeliveryBackoff(base, max time.Duration) Option {
+	return func(o *options) {
+		o.redeliveryBase = base
+		o.redeliveryMax = max
+	}
+}
+
+// WithExpiryRateLimit caps the number of expiry callbacks that run per second
+// across the whole store, using a token bucket that holds up to perSecond
+// tokens. Events still leave the store when their timer fires, but callbacks
+// exceeding the rate are queued and run in deadline order as tokens become
+// available. Unlike a worker pool, the limit applies to throughput over time
+// rather than to the number of callbacks running at once. A perSecond of zero
+// or less disables the limit.
+func WithExpiryRateLimit(perSecond int) Option {
+	return func(o *options) { o.expiryRate = perSecond }
+}
+
+// WithMinRearmInterval debounces deadline changes made by Reschedule and
+// Touch. After a timer is armed, changes within d only record the requested
+// deadline; the timer itself is reset at most once per window. The effective
+// fire time is always the last deadline requested: a later one is honoured by
+// re-arming when the timer fires, and an earlier one fires no later than the
+// end of the current window. This protects the runtime from clients resetting
+// the same timer thousands of times a second.
+func WithMinRearmInterval(d time.Duration) Option {
+	return func(o *options) { o.minRearm = d }
+}
+
+// WithDeferredDelete makes a Persistent store batch the DB deletions of
+// expired events instead of deleting each one as it fires. Deletions are
+// flushed every interval, as soon as maxBatch of them are pending, and on
+// Close. They go through DeleteBatch if the DB implements BatchDeleter, and
+// through Delete one by one otherwise. Cancel still deletes synchronously.
+//
+// An expired event stays in the DB until its deletion is flushed, so a crash
+// can leave rows for events that already fired up to interval ago, which a
+// later Recover would fire again.
+func WithDeferredDelete(interval time.Duration, maxBatch int) Option {
+	return func(o *options) {
+		o.deleteInterval = interval
+		o.deleteBatch = maxBatch
+	}
+}
+
+// WithCapacity limits the total weight of the events a store holds at once.
+// Events weigh 1 unless they implement Weighter, so without weighted events
+// this limits the number of events. Start fails with ErrCapacityExceeded for
+// an event that would take the store over the limit, unless an evictor makes
+// room for it (see Simple.SetEvictor). A capacity of zero or less means no
+// limit.
+func WithCapacity(capacity int64) Option {
+	return func(o *options) { o.capacity = capacity }
+}
+
+// WithDriftCorrection makes timers follow the wall clock for far-future
+// deadlines. Instead of sleeping until the deadline in one go, a timer wakes
+// up at least every checkInterval, recomputes the remaining time from the wall
+// clock and re-arms for the rest, so the final wait is at most checkInterval
+// long. This keeps alarm-style events scheduled hours or days ahead on time
+// across suspend and resume or clock adjustments, at the cost of one wakeup
+// per checkInterval per event. Events fire up to checkInterval late after the
+// clock jumps forward past their deadline while the timer is sleeping.
+func WithDriftCorrection(checkInterval time.Duration) Option {
+	return func(o *options) { o.driftCheck = checkInterval }
+}
+
+// WithCloseMode sets what Close does with the events still stored. The
+// default is CloseDrop.
+func WithCloseMode(mode CloseMode) Option {
+	return func(o *options) { o.closeMode = mode }
+}
+
+// WithReplaceMode sets what Start does for an id that is already present. The
+// default is ReplaceAlways. Whatever the mode decides, the stored event and
+// its timer always agree: a replaced event gets a fresh timer for the
+// incoming deadline, and a kept one keeps its timer untouched.
+func WithReplaceMode(mode ReplaceMode) Option {
+	return func(o *options) { o.replaceMode = mode }
+}
+
+// WithExpiryDispatch sets where expiry callbacks run. The default is
+// DispatchInline.
+func WithExpiryDispatch(mode Dispatch) Option {
+	return func(o *options) { o.dispatch = mode }
+}
+
+// WithPoolSize sets the number of workers used by DispatchPool. The default is
+// runtime.GOMAXPROCS(0).
+func WithPoolSize(n int) Option {
+	return func(o *options) { o.poolSize = n }
+}
+
+// WithAckDelivery configures the delivery of events started with Enqueue. At
+// most maxUnacked delivered events may await Ack or Nack at once; further
+// expiring events wait until one is settled. A delivered event that is
+// neither acknowledged nor rejected within timeout is rejected as if Nack was
+// called. The defaults are one minute and 100.
+func WithAckDelivery(timeout time.Duration, maxUnacked int) Option {
+	return func(o *options) {
+		o.ackTimeout = timeout
+		o.maxUnacked = maxUnacked
+	}
+}
+
+// WithLifetimeHistogram records how long events live, from Start to their
+// removal by expiry, cancellation or replacement, into a histogram read with
+// LifetimeHistogram. bounds are the upper bounds of the buckets, in
+// increasing order; a last bucket counts the lifetimes above the largest
+// bound.
+func WithLifetimeHistogram(bounds ...time.Duration) Option {
+	return func(o *options) {
+		o.lifetimes = bounds
+	}
+}
+
+// WithAdaptiveGranularity lets the store trade timer precision for fewer
+// wakeups under load. While the average rate of Start exceeds threshold per
+// second, timers are armed for the next multiple of coarse at or after their
+// deadline, so events due close together fire together and the runtime wakes
+// up once per coarse interval instead of once per event. Events then fire up
+// to coarse late. The average is an exponential moving average over
+// one-second windows, halving the weight of older windows each second.
+//
+// To avoid flapping around the threshold, coarse mode is left only once the
+// average drops below half of threshold. Switching modes does not re-arm the
+// timers already set.
+func WithAdaptiveGranularity(threshold int, coarse time.Duration) Option {
+	return func(o *options) {
+		o.coarseRate = threshold
+		o.coarse = coarse
+	}
+}
+
+// WithLazyPersist makes Persistent.Start keep events due more than window in
+// the future in memory only, and put them to the DB once they come within
+// window of their deadline. This saves a DB row, which might go stale, for
+// every far-future event.
+//
+// The price is durability: until it is put, the event exists in memory only,
+// so a crash or a Close loses it and Recover cannot bring it back. An event
+// started at t for deadline d is durable from d-window on, when its promotion
+// timer fires. Promotion puts the event without holding the store lock, and
+// deletes it again if a concurrent Cancel or Start of the same id removed it
+// during the put, so that no stale row is left behind.
+// Register a hook with Persistent.OnPromote to observe promotions. Only Start
+// persists lazily; the other start methods put at once.
+func WithLazyPersist(window time.Duration) Option {
+	return func(o *options) { o.lazyWindow = window }
+}
+
+// WithResumeDrainRate makes Resume release the callbacks held back while the
+// store was paused at most perSecond per second, in deadline order, instead of
+// all at once. This keeps a long pause from turning into a spike downstream
+// the moment the store resumes. It applies to the held-back callbacks only;
+// WithExpiryRateLimit limits all of them.
+func WithResumeDrainRate(perSecond int) Option {
+	return func(o *options) { o.resumeRate = perSecond }
+}
+
+// WithTTLBounds guards the store against events due absurdly soon or far
+// away, such as in the past or ten years out. At Start, an event due in less
+// than min or more than max from now is brought within bounds or rejected
+// with ErrTTLOutOfBounds, depending on mode. A bound of zero or less is not
+// checked. With TTLClamp the timer is armed for the clamped deadline, and an
+// event implementing ExpirySetter gets its ExpireAt updated so that Get and
+// the DB of a Persistent store agree with the timer.
+func WithTTLBounds(min, max time.Duration, mode TTLMode) Option {
+	return func(o *options) {
+		o.ttlMin, o.ttlMax = min, max
+		o.ttlMode = mode
+	}
+}
+
+// WithPanicPolicy sets what happens when an expiry callback panics. The
+// default is PanicPropagate.
+func WithPanicPolicy(policy PanicPolicy) Option {
+	return func(o *options) { o.panicPolicy = policy }
+}
+
+// WithAfterFuncBatching makes expiries that go to the handler set with
+// SetBatchHandler wait for up to flush and reach it together, in one call
+// per flush interval, instead of one handler call each. Timers still fire one
+// by one, and events still leave the store as they do; only the handler
+// calls are batched. This cuts the callback overhead when many events are due
+// at nearly the same moment, at the cost of up to flush of added latency for
+// each expiry. Close waits for the pending batch. A flush of zero or less
+// disables batching.
+func WithAfterFuncBatching(flush time.Duration) Option {
+	return func(o *options) { o.batchFlush = flush }
+}
+
+// WithDeadlineAlignment makes timers fire on the boundaries epoch plus a
+// multiple of boundary, such as the 15-minute windows of a downstream system:
+// the time a timer is armed for is rounded up to the next boundary at or
+// after it, in either direction from epoch. An event thus fires up to one
+// boundary late, and events due past the same boundary fire together, while
+// Get and the DB of a Persistent store keep its true ExpireAt. The delay
+// counts as lateness for Stats and OnLate. Boundaries are measured on the
+// wall clock. A boundary of zero or less disables alignment.
+func WithDeadlineAlignment(boundary time.Duration, epoch time.Time) Option {
+	return func(o *options) {
+		o.alignBoundary, o.alignEpoch = boundary, epoch
+	}
+}
+
+// WithErrorBuffer sets how many errors of StartFallible callbacks the channel
+// returned by Errors buffers before dropping them. The default is 64.
+func WithErrorBuffer(n int) Option {
+	return func(o *options) { o.errorBuffer = n }
+}
+
+// WithRefreshOnGet gives events a sliding expiration, as a session cache
+// wants: every Get of an event moves its deadline to ttl from now, as Touch
+// does, so only events nobody reads expire. Get then writes as well as reads:
+// it re-arms the timer, calls the OnReschedule hook and, if the event
+// implements ExpirySetter, updates its ExpireAt; an event that does not keeps
+// its original ExpireAt, which then no longer tells when it expires. A
+// Persistent store does not put refreshed events to the DB. A ttl of zero or
+// less keeps Get read-only.
+func WithRefreshOnGet(ttl time.Duration) Option {
+	return func(o *options) { o.refreshTTL = ttl }
+}
+
+// WithDependentMode sets what happens to the events waiting, after
+// StartAfterEvent, for an event that leaves the store without expiring. The
+// default is DependentCancel.
+func WithDependentMode(mode DependentMode) Option {
+	return func(o *options) { o.dependentMode = mode }
+}
+
+// WithCallbackTimeout bounds how long an expiry callback is expected to run.
+// A callback still running d after it was called is reported to the hook set
+// with OnCallbackTimeout, and callbacks started with StartTraced get a
+// context with that deadline. The store cannot stop a callback, so it keeps
+// running after the timeout, unless it honours its context. In DispatchPool
+// mode, every callback runs on a goroutine of its own, and the worker stops
+// waiting for it on timeout to take on the next one: a stuck callback then no
+// longer holds a worker, but overrunning callbacks are no longer bounded by
+// the pool size, and neither Close nor State sees them after the timeout. A
+// d of zero or less disables the timeout.
+func WithCallbackTimeout(d time.Duration) Option {
+	return func(o *options) { o.callbackTimeout = d }
+}
+
+// WithReadThrough makes a Persistent store treat memory as a cache of its DB,
+// which must be a Getter: Get loads an event missing from memory from the DB
+// and arms it, as Load does, and Cancel deletes an event missing from memory
+// from the DB without arming it. The DB is then the source of truth for the
+// ids not in memory, and each miss costs a DB lookup; Get and Cancel report
+// the event absent whether the DB does not have it or the lookup failed,
+// which Load tells apart. It has no effect on a Simple store.
+func WithReadThrough() Option {
+	return func(o *options) { o.readThrough = true }
+}
+
+// redeliveryDelay returns the delay before the given redelivery attempt,
+// starting at zero.
+func (o *options) redeliveryDelay(attempt int) time.Duration {
+	base, limit := o.redeliveryBase, o.redeliveryMax
+	if base <= 0 {
+		// Zero value store, or a nonsensical option.
+		base, limit = defaultRedeliveryBase, defaultRedeliveryMax
+	}
+
+	d := base
+	for i := 0; i < attempt && d < limit; i++ {
+		d *= 2
+	}
+
+	return min(d, limit)
+}
@@ -0,0 +1,98 @@
+package timerstore
+
+// PanicPolicy selects what happens when an expiry callback panics. Whatever
+// the policy, the hook registered with OnPanic, if any, is called first with
+// the recovered value. The event has already left the store by then.
+type PanicPolicy int
+
+const (
+	// PanicPropagate lets the panic go on, on the goroutine that ran the
+	// callback: the timer goroutine, a worker of the pool, or the goroutine
+	// calling Close or Resume when they run callbacks. Unless something up
+	// that goroutine recovers it, which the store never does, the program
+	// crashes.
+	PanicPropagate PanicPolicy = iota
+	// PanicRecover recovers the panic and moves on to the next expiry.
+	PanicRecover
+	// PanicRethrow recovers the panic and panics again with the same value
+	// on a new goroutine, so that it crashes the program however the
+	// callback was run, even from a goroutine whose caller would recover it.
+	PanicRethrow
+	// PanicRetryOnce recovers the panic and calls the callback once more. A
+	// second panic is recovered too, reported to OnPanic again, and
+	// dropped.
+	PanicRetryOnce
+)
+
+// OnPanic registers fn to be called with the id, the event and the recovered
+// value whenever an expiry callback panics, under any PanicPolicy. fn runs on
+// the goroutine that ran the callback. Passing nil removes the hook.
+func (s *Simple[ID, E]) OnPanic(fn func(id ID, event E, v any)) {
+	if fn == nil {
+		s.onPanic.Store(nil)
+		return
+	}
+
+	s.onPanic.Store(&fn)
+}
+
+// call runs the callback fn of an expired event, applying the panic policy
+// and the callback timeout.
+func (s *Simple[ID, E]) call(id ID, event E, fn func()) {
+	if t := s.opts.callbackTimeout; t > 0 {
+		s.timed(id, t, func() { s.guard(id, event, fn) })
+		return
+	}
+
+	s.guard(id, event, fn)
+}
+
+// guard runs fn, applying the panic policy.
+func (s *Simple[ID, E]) guard(id ID, event E, fn func()) {
+	policy := s.opts.panicPolicy
+	if policy == PanicPropagate && s.onPanic.Load() == nil {
+		fn()
+		return
+	}
+
+	for attempt := 0; ; attempt++ {
+		v, panicked := try(fn)
+		if !panicked {
+			return
+		}
+
+		if h := s.onPanic.Load(); h != nil {
+			(*h)(id, event, v)
+		}
+
+		switch policy {
+		case PanicRecover:
+			return
+		case PanicRethrow:
+			go panic(v)
+			return
+		case PanicRetryOnce:
+			if attempt > 0 {
+				return
+			}
+		default:
+			panic(v)
+		}
+	}
+}
+
+// try calls fn and returns the value of its panic, if it panicked.
+func try(fn func()) (v any, panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			v, panicked = r, true
+		}
+	}()
+
+	fn()
+	return nil, false
+}
+
+// OnPanic registers fn to be called whenever an expiry callback panics. See
+// Simple.OnPanic.
+func (p *Persistent[ID, E]) OnPanic(fn func(id ID, event E, v any)) { p.s.OnPanic(fn) }
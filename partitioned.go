@@ -0,0 +1,93 @@
+package timerstore
+
+import (
+	"errors"
+	"time"
+)
+
+var _ Store[any, Event] = &Partitioned[any, Event]{}
+
+// Partitioned is a Store that spreads ids over a fixed number of independent
+// Simple stores by hashing them. Every partition has its own lock, options
+// and timers, and can be used and closed on its own through Partition.
+type Partitioned[ID comparable, E Event] struct {
+	parts []*Simple[ID, E]
+	hash  func(ID) uint64
+}
+
+// NewPartitioned creates a Partitioned store of n partitions, each a Simple
+// configured with opts. An id is routed to partition hash(id) % n, so hash
+// must return the same value for the same id every time. NewPartitioned
+// panics if n is less than one or hash is nil.
+func NewPartitioned[ID comparable, E Event](n int, hash func(ID) uint64, opts ...Option) *Partitioned[ID, E] {
+	if n < 1 || hash == nil {
+		panic("timerstore: NewPartitioned needs a hash and at least one partition")
+	}
+
+	p := &Partitioned[ID, E]{parts: make([]*Simple[ID, E], n), hash: hash}
+	for i := range p.parts {
+		p.parts[i] = NewSimple[ID, E](opts...)
+	}
+
+	return p
+}
+
+func (p *Partitioned[ID, E]) of(id ID) *Simple[ID, E] {
+	return p.parts[p.hash(id)%uint64(len(p.parts))]
+}
+
+// Partition returns the i-th partition.
+func (p *Partitioned[ID, E]) Partition(i int) *Simple[ID, E] { return p.parts[i] }
+
+// Partitions returns the number of partitions.
+func (p *Partitioned[ID, E]) Partitions() int { return len(p.parts) }
+
+// Start starts the event in the partition of id. See Simple.Start.
+func (p *Partitioned[ID, E]) Start(id ID, event E, atExpire func()) error {
+	return p.of(id).Start(id, event, atExpire)
+}
+
+// Cancel cancels the event of id in its partition. See Simple.Cancel.
+func (p *Partitioned[ID, E]) Cancel(id ID) (E, bool) { return p.of(id).Cancel(id) }
+
+// Get returns the event stored for id in its partition.
+func (p *Partitioned[ID, E]) Get(id ID) (E, bool) { return p.of(id).Get(id) }
+
+// Reschedule moves the deadline of id in its partition. See
+// Simple.Reschedule.
+func (p *Partitioned[ID, E]) Reschedule(id ID, at time.Time) (E, bool) {
+	return p.of(id).Reschedule(id, at)
+}
+
+// Len returns the number of events stored across all partitions. Partitions
+// are counted one after another, so the total is not a consistent snapshot
+// while events are started or removed concurrently.
+func (p *Partitioned[ID, E]) Len() int {
+	n := 0
+	for _, s := range p.parts {
+		n += s.Len()
+	}
+
+	return n
+}
+
+// CancelAll cancels every event in every partition and returns the number of
+// events cancelled.
+func (p *Partitioned[ID, E]) CancelAll() int {
+	n := 0
+	for _, s := range p.parts {
+		n += s.CancelAll()
+	}
+
+	return n
+}
+
+// Close closes every partition. See Simple.Close.
+func (p *Partitioned[ID, E]) Close() error {
+	errs := make([]error, len(p.parts))
+	for i, s := range p.parts {
+		errs[i] = s.Close()
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,38 @@
+package timerstore
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestPartitionedFanOut(t *testing.T) {
+	hash := func(id string) uint64 {
+		n, _ := strconv.Atoi(id)
+		return uint64(n)
+	}
+	p := NewPartitioned[string, testEvent](4, hash)
+	defer p.Close()
+
+	at := time.Now().Add(time.Hour)
+	for i := range 10 {
+		if err := p.Start(strconv.Itoa(i), testEvent{At: at}, func() {}); err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+	}
+	for i := range p.Partitions() {
+		if n := p.Partition(i).Len(); n == 0 {
+			t.Errorf("partition %d is empty; want events in every partition", i)
+		}
+	}
+
+	if n := p.Len(); n != 10 {
+		t.Errorf("Len = %d; want 10", n)
+	}
+	if n := p.CancelAll(); n != 10 {
+		t.Errorf("CancelAll = %d; want 10", n)
+	}
+	if n := p.Len(); n != 0 {
+		t.Errorf("Len after CancelAll = %d; want 0", n)
+	}
+}
@@ -0,0 +1,67 @@
+package timerstore
+
+import "slices"
+
+// Pause holds back expiry callbacks. Timers keep running while the store is
+// paused, and events still leave the store when they expire, but their
+// callbacks are held until Resume. Starting, cancelling and rescheduling
+// events work as usual. Pausing a paused store does nothing.
+//
+// Close releases the held callbacks in deadline order in the flush modes,
+// and drops them in CloseDrop mode.
+func (s *Simple[ID, E]) Pause() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.paused = true
+}
+
+// Resume releases the callbacks held back since Pause, in deadline order, and
+// lets later expiries through again. With WithResumeDrainRate they are
+// released gradually; otherwise they are all dispatched at once. Resuming a
+// store that is not paused does nothing.
+func (s *Simple[ID, E]) Resume() {
+	s.mu.Lock()
+	if !s.paused {
+		s.mu.Unlock()
+		return
+	}
+	s.paused = false
+	held := s.backlog
+	s.backlog = nil
+	s.inflight.Add(len(held))
+	s.mu.Unlock()
+
+	slices.SortFunc(held, func(a, b entry[ID, E]) int { return a.d.deadline.Compare(b.d.deadline) })
+	if s.opts.resumeRate <= 0 {
+		for _, e := range held {
+			s.dispatch(e.id, e.d)
+		}
+		return
+	}
+
+	drain := newLimiter(s.opts.resumeRate, func(fn func()) { fn() })
+	drain.tokens = 1 // no initial burst
+	for _, e := range held {
+		drain.submit(e.d.deadline, func() { s.dispatch(e.id, e.d) })
+	}
+}
+
+// Paused reports whether the store is paused.
+func (s *Simple[ID, E]) Paused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.paused
+}
+
+// Pause holds back expiry callbacks. Events still expire from memory and are
+// deleted from the DB only once their callbacks are released, so a crash
+// while paused recovers them. See Simple.Pause.
+func (p *Persistent[ID, E]) Pause() { p.s.Pause() }
+
+// Resume releases the callbacks held back since Pause. See Simple.Resume.
+func (p *Persistent[ID, E]) Resume() { p.s.Resume() }
+
+// Paused reports whether the store is paused.
+func (p *Persistent[ID, E]) Paused() bool { return p.s.Paused() }
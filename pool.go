@@ -0,0 +1,68 @@
+package timerstore
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Dispatch selects where expiry callbacks run.
+type Dispatch int
+
+const (
+	// DispatchInline runs each callback on the goroutine of the timer that
+	// fired. Callbacks of different events run concurrently, one goroutine
+	// each, and nothing bounds how many run at once.
+	DispatchInline Dispatch = iota
+	// DispatchGoroutine hands each callback to a new goroutine, so the timer
+	// goroutine returns at once and a slow callback is isolated from the
+	// store's own bookkeeping. Like DispatchInline it is unbounded, and
+	// callbacks of events expiring together may start in any order.
+	DispatchGoroutine
+	// DispatchPool queues callbacks to a fixed pool of workers (see
+	// WithPoolSize), bounding how many run at once. When all workers are busy
+	// and the queue is full, expiring timers block until a worker frees up,
+	// so slow callbacks delay the expiry of other events rather than piling
+	// up goroutines. Callbacks start in the order their events expired.
+	DispatchPool
+)
+
+// pool is a fixed set of workers running queued callbacks. The workers are
+// started on the first submit.
+type pool struct {
+	size int
+	once sync.Once
+	jobs chan func()
+}
+
+func newPool(size int) *pool {
+	if size <= 0 {
+		size = runtime.GOMAXPROCS(0)
+	}
+
+	return &pool{size: size}
+}
+
+func (p *pool) submit(fn func()) {
+	p.once.Do(p.start)
+	p.jobs <- fn
+}
+
+func (p *pool) start() {
+	p.jobs = make(chan func(), p.size)
+	for range p.size {
+		go func() {
+			for fn := range p.jobs {
+				fn()
+			}
+		}()
+	}
+}
+
+// close stops the workers once the queued callbacks have run. It must not be
+// called concurrently with submit.
+func (p *pool) close() {
+	p.once.Do(func() {})
+	if p.jobs != nil {
+		close(p.jobs)
+	}
+}
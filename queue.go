@@ -0,0 +1,175 @@
+package timerstore
+
+import (
+	"slices"
+	"time"
+)
+
+// Push stores the event without an expiry callback. It is Start with a nil
+// atExpire: the event still leaves the store when it expires, and then goes to
+// the batch handler or the handler set with SetExpiryHandler, if any. Use
+// Schedule for an event that calls nothing at all. Push is meant for driving
+// the store as a priority queue with Pop.
+func (s *Simple[ID, E]) Push(id ID, event E) error {
+	return s.Start(id, event, nil)
+}
+
+// Pop removes and returns the event with the earliest deadline, whether or
+// not that deadline has passed, and stops its timer. Pop bypasses the expiry
+// callback entirely: the event counts neither as cancelled nor as expired. ok
+// is false if the store is empty.
+//
+// Simple keeps no ordering, so Pop scans every stored event and costs O(n).
+func (s *Simple[ID, E]) Pop() (id ID, event E, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var first *data[E]
+	for i, d := range s.m {
+		if first == nil || d.deadline.Before(first.deadline) {
+			id, first = i, d
+		}
+	}
+
+	if first == nil {
+		return id, event, false
+	}
+
+	s.stop(first)
+	s.remove(id, first)
+	return id, first.event, true
+}
+
+// NthExpiry returns the deadline of the n-th soonest event, counting from one,
+// so NthExpiry(1) is the earliest deadline. ok is false if n is less than one
+// or fewer than n events are stored.
+//
+// Simple keeps no ordering, so NthExpiry copies every deadline and partially
+// sorts them: it costs O(n) time on average and O(n) memory in the number of
+// stored events, however small the requested n.
+func (s *Simple[ID, E]) NthExpiry(n int) (time.Time, bool) {
+	s.mu.Lock()
+	if n < 1 || n > len(s.m) {
+		s.mu.Unlock()
+		return time.Time{}, false
+	}
+
+	deadlines := make([]time.Time, 0, len(s.m))
+	for _, d := range s.m {
+		deadlines = append(deadlines, d.deadline)
+	}
+	s.mu.Unlock()
+
+	return nthSmallest(deadlines, n-1), true
+}
+
+// nthSmallest returns the k-th smallest time in ts, counting from zero,
+// reordering ts in place.
+func nthSmallest(ts []time.Time, k int) time.Time {
+	lo, hi := 0, len(ts)-1
+	for lo < hi {
+		pivot := ts[lo+(hi-lo)/2]
+		i, j := lo, hi
+		for i <= j {
+			for ts[i].Before(pivot) {
+				i++
+			}
+			for pivot.Before(ts[j]) {
+				j--
+			}
+			if i <= j {
+				ts[i], ts[j] = ts[j], ts[i]
+				i++
+				j--
+			}
+		}
+
+		switch {
+		case k <= j:
+			hi = j
+		case k >= i:
+			lo = i
+		default:
+			return ts[k]
+		}
+	}
+
+	return ts[k]
+}
+
+// HasExpiryWithin reports whether any stored event is due within d from now,
+// including events already past due. It is false for an empty store. Simple
+// keeps no ordering, so HasExpiryWithin scans the stored events, holding the
+// store lock, but stops at the first one due in time.
+func (s *Simple[ID, E]) HasExpiryWithin(d time.Duration) bool {
+	limit := time.Now().Add(d)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.m {
+		if !e.deadline.After(limit) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasExpiryWithin reports whether any event stored in memory is due within d
+// from now. See Simple.HasExpiryWithin.
+func (p *Persistent[ID, E]) HasExpiryWithin(d time.Duration) bool { return p.s.HasExpiryWithin(d) }
+
+// NextFreeWindow returns the start of the earliest gap of at least minLength
+// during which no event is due, searching the horizon from now to within from
+// now. A gap runs from one deadline to the next, with no deadline strictly
+// inside it; it may start now, if no event is due before now plus minLength,
+// and it may end at the end of the horizon, but it must fit within the
+// horizon. So a gap starting with the deadline of an event begins as that
+// event fires, and events already past due count as due now. ok is false if
+// no such gap exists, which is always the case if minLength is longer than
+// within. It reads the store once; events started or rescheduled afterwards
+// may fall in the window.
+//
+// Simple keeps no ordering, so NextFreeWindow copies and sorts the deadlines
+// within the horizon, costing O(n log n) in the number of such events.
+func (s *Simple[ID, E]) NextFreeWindow(minLength, within time.Duration) (start time.Time, ok bool) {
+	if minLength > within {
+		return time.Time{}, false
+	}
+
+	now := time.Now()
+	end := now.Add(within)
+	s.mu.Lock()
+	var deadlines []time.Time
+	for _, d := range s.m {
+		if !d.deadline.After(end) {
+			deadlines = append(deadlines, maxTime(d.deadline, now))
+		}
+	}
+	s.mu.Unlock()
+	slices.SortFunc(deadlines, time.Time.Compare)
+
+	start = now
+	for _, at := range deadlines {
+		if at.Sub(start) >= minLength {
+			return start, true
+		}
+		start = at
+	}
+	if end.Sub(start) >= minLength {
+		return start, true
+	}
+
+	return time.Time{}, false
+}
+
+// NextFreeWindow returns the start of the earliest gap of at least minLength
+// without a deadline of an event stored in memory. See Simple.NextFreeWindow.
+func (p *Persistent[ID, E]) NextFreeWindow(minLength, within time.Duration) (time.Time, bool) {
+	return p.s.NextFreeWindow(minLength, within)
+}
+
+// NthExpiry returns the deadline of the n-th soonest event stored in memory.
+// See Simple.NthExpiry.
+func (p *Persistent[ID, E]) NthExpiry(n int) (time.Time, bool) { return p.s.NthExpiry(n) }
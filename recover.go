@@ -0,0 +1,196 @@
+package timerstore
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Recover re-arms the timers of all events stored in the DB, typically after a
+// restart. The events are not put to the DB again. Since callbacks cannot be
+// persisted, recovered events are handled by the default handler when they
+// expire (see SetDefaultHandler). Events that expired while the process was
+// down fire immediately. Ids already stored in memory are skipped, so that
+// events started before Recover keep their callbacks. With SetFiredFilter,
+// ids that fired recently are skipped and left in the DB.
+//
+// If onProgress is not nil, it is called after each event is handled with the
+// number of events done so far and the total listed by the DB. Recover
+// stops as soon as ctx is done, returning ctx.Err(): the events re-armed so
+// far stay armed and the rest are left in the DB for a later Recover. Recover
+// returns the number of events re-armed, also when it fails partway.
+func (p *Persistent[ID, E]) Recover(ctx context.Context, onProgress func(done, total int)) (int, error) {
+	lister, ok := p.db.(Lister[ID, E])
+	if !ok {
+		return 0, errNotImplemented("Lister")
+	}
+
+	entries, err := lister.List()
+	if err != nil {
+		return 0, err
+	}
+
+	fired := p.fired.Load()
+	n := 0
+	for i, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+
+		if fired == nil || !fired.has(e.ID) {
+			ok, err := p.recover(e.ID, e.Event)
+			if err != nil {
+				return n, err
+			}
+			if ok {
+				n++
+			}
+		}
+
+		if onProgress != nil {
+			onProgress(i+1, len(entries))
+		}
+	}
+
+	return n, nil
+}
+
+// recover arms an event listed by the DB, marking it for OnRecoveredExpiry if
+// it is already past due. It reports false, arming nothing, if id is already
+// stored in memory.
+func (p *Persistent[ID, E]) recover(id ID, event E) (bool, error) {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+
+	if _, ok := p.s.m[id]; ok {
+		return false, nil
+	}
+
+	at := event.ExpireAt()
+	d, err := p.s.add(id, event, at, nil)
+	if err != nil {
+		return false, kept(err)
+	}
+	d.recovered = !at.After(time.Now())
+
+	return true, nil
+}
+
+// OnRecoveredExpiry registers fn to be called for every event that Recover
+// found already past due, when it fires. Such an event fires at once, as a
+// catch-up for the time the process was down, rather than at the time it was
+// scheduled for; fn gets both, so the handler can tell how stale the expiry
+// is. fn runs just before the event's callback, or the default handler, on
+// the same goroutine. It is called on the recovery path only. Passing nil
+// removes the hook.
+func (p *Persistent[ID, E]) OnRecoveredExpiry(fn func(id ID, scheduledFor, firedAt time.Time)) {
+	if fn == nil {
+		p.s.onRecovered.Store(nil)
+		return
+	}
+
+	p.s.onRecovered.Store(&fn)
+}
+
+// ReconcileReport lists the differences Reconcile found between the events in
+// memory and those in the DB.
+type ReconcileReport[ID comparable] struct {
+	// MissingInDB holds the ids stored in memory but not listed by the DB.
+	MissingInDB []ID
+	// MissingInMemory holds the ids listed by the DB but not stored in
+	// memory.
+	MissingInMemory []ID
+	// Mismatched holds the ids stored in both whose events disagree on
+	// ExpireAt.
+	Mismatched []ExpiryMismatch[ID]
+}
+
+// ExpiryMismatch is an id whose event has a different ExpireAt in memory and
+// in the DB.
+type ExpiryMismatch[ID comparable] struct {
+	ID             ID
+	Memory, Stored time.Time
+}
+
+// Consistent reports whether the report found no difference.
+func (r ReconcileReport[ID]) Consistent() bool {
+	return len(r.MissingInDB) == 0 && len(r.MissingInMemory) == 0 && len(r.Mismatched) == 0
+}
+
+// Reconcile compares the events stored in memory with those listed by the DB,
+// which must be a Lister, and reports the ids found in only one of them and
+// the ids whose ExpireAt differs. It is a safety check for long-running
+// services and for custom DB implementations.
+//
+// If heal is true, Reconcile also repairs what it found, taking memory as the
+// authority: events missing in the DB or disagreeing with it are put again,
+// and events missing in memory are armed as Recover does, for the default
+// handler. The report still lists everything found before healing.
+//
+// Memory and DB are read one after the other, so operations running
+// meanwhile show up as differences. Expired events whose deletion is still
+// deferred by WithDeferredDelete, events skipped by SetFiredFilter and
+// events delivered with Enqueue but not yet acknowledged are in the DB only
+// by design; healing re-arms them. Likewise, events not yet put by
+// WithLazyPersist are in memory only, and healing puts them early.
+func (p *Persistent[ID, E]) Reconcile(heal bool) (ReconcileReport[ID], error) {
+	var report ReconcileReport[ID]
+	lister, ok := p.db.(Lister[ID, E])
+	if !ok {
+		return report, errNotImplemented("Lister")
+	}
+
+	entries, err := lister.List()
+	if err != nil {
+		return report, err
+	}
+
+	memory := p.s.Freeze()
+	stored := make(map[ID]struct{}, len(entries))
+	var missing []Entry[ID, E]
+	for _, e := range entries {
+		stored[e.ID] = struct{}{}
+		event, ok := memory.Get(e.ID)
+		switch {
+		case !ok:
+			report.MissingInMemory = append(report.MissingInMemory, e.ID)
+			missing = append(missing, e)
+		case !event.ExpireAt().Equal(e.Event.ExpireAt()):
+			report.Mismatched = append(report.Mismatched, ExpiryMismatch[ID]{e.ID, event.ExpireAt(), e.Event.ExpireAt()})
+		}
+	}
+
+	memory.Range(func(id ID, _ E) bool {
+		if _, ok := stored[id]; !ok {
+			report.MissingInDB = append(report.MissingInDB, id)
+		}
+
+		return true
+	})
+
+	if !heal {
+		return report, nil
+	}
+
+	var errs []error
+	put := func(id ID) {
+		if event, ok := memory.Get(id); ok {
+			if err := p.db.Put(id, event); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	for _, id := range report.MissingInDB {
+		put(id)
+	}
+	for _, m := range report.Mismatched {
+		put(m.ID)
+	}
+	for _, e := range missing {
+		if _, err := p.recover(e.ID, e.Event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return report, errors.Join(errs...)
+}
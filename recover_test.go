@@ -0,0 +1,32 @@
+package timerstore
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRecoverKeepsLiveEvents(t *testing.T) {
+	db := newMapDB[testEvent]()
+	p := NewPersistentStore[string, testEvent](db)
+	defer p.Close()
+
+	fired := make(chan struct{})
+	if err := p.Start("a", testEvent{At: time.Now().Add(20 * time.Millisecond)}, func() { close(fired) }); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	n, err := p.Recover(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Recover: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("Recover armed %d events; want 0", n)
+	}
+
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("original callback not called")
+	}
+}
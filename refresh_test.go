@@ -0,0 +1,27 @@
+package timerstore
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRefreshOnGet(t *testing.T) {
+	s := NewSimple[string, testEvent](WithRefreshOnGet(time.Hour))
+	defer s.Close()
+
+	var fired atomic.Bool
+	start := time.Now()
+	s.Start("a", testEvent{At: start.Add(20 * time.Millisecond)}, func() { fired.Store(true) })
+	if _, ok := s.Get("a"); !ok {
+		t.Fatal("Get found no event")
+	}
+	if at, _ := s.NthExpiry(1); at.Before(start.Add(time.Hour)) {
+		t.Errorf("deadline after Get is %v; want an hour from the Get", at.Sub(start))
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if fired.Load() || s.Len() != 1 {
+		t.Error("event read with Get expired at its original deadline")
+	}
+}
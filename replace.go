@@ -0,0 +1,67 @@
+package timerstore
+
+// Replace atomically swaps the event stored for id with event and moves its
+// timer to the new event's ExpireAt, so the stored event and its timer never
+// disagree. If atExpire is not nil it replaces the callback too; otherwise the
+// previous callback is kept. It returns the previous event and whether id was
+// present; if it was not, nothing is stored. Replace ignores the replace mode
+// and does not apply the capacity limit, but keeps the weight of the store and
+// the pinned count up to date.
+func (s *Simple[ID, E]) Replace(id ID, event E, atExpire func()) (E, bool) {
+	s.mu.Lock()
+	d, ok := s.m[id]
+	if !ok {
+		s.mu.Unlock()
+		var zeroE E
+		return zeroE, false
+	}
+
+	prev, old, at := d.event, d.deadline, event.ExpireAt()
+	d.event = event
+	s.weight -= d.weight
+	d.weight = weightOf(event)
+	s.weight += d.weight
+	if pinned := pinnedOf(event); pinned != d.pinned {
+		d.pinned = pinned
+		if pinned {
+			s.pinned++
+		} else {
+			s.pinned--
+		}
+	}
+	if atExpire != nil {
+		d.atExpire, d.silent = atExpire, false
+	}
+
+	s.moveDeadline(d, at)
+	s.mu.Unlock()
+
+	s.rescheduled(id, old, at)
+	return prev, true
+}
+
+// Replace atomically swaps the event stored in memory for id and moves its
+// timer, as Simple.Replace does. The new event is put to the DB first; if that
+// fails, nothing changes and the error is returned. If id is not present in
+// memory, nothing is put, and if the event leaves memory before it is
+// replaced, the put is undone by deleting the new event.
+func (p *Persistent[ID, E]) Replace(id ID, event E, atExpire func()) (E, bool, error) {
+	if _, ok := p.s.peek(id); !ok {
+		var zeroE E
+		return zeroE, false, nil
+	}
+
+	if err := p.db.Put(id, event); err != nil {
+		var zeroE E
+		return zeroE, false, err
+	}
+
+	prev, ok := p.s.Replace(id, event, atExpire)
+	if !ok {
+		// The event left memory after the check, and the put stored the new
+		// one in its place.
+		p.delete(id, event, ReasonCancelled)
+	}
+
+	return prev, ok, nil
+}
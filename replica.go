@@ -0,0 +1,132 @@
+package timerstore
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// errReplicaReschedule is recorded when a Reschedule cannot be mirrored
+// because the replica is not a Rescheduler.
+var errReplicaReschedule = errors.New("timerstore: replica cannot reschedule")
+
+// Replicated is a Store that forwards to a primary store and mirrors every
+// successful Start, Cancel and Reschedule to a standby replica, so that the
+// replica holds the same events with timers already armed when it has to take
+// over. Create one with WithReplica.
+//
+// Replication is synchronous: an operation returns after it was applied to
+// both stores, so it takes as long as both together, and the replica is never
+// behind once the operation has returned. Operations racing on different
+// goroutines may be applied to the replica in a different order than to the
+// primary. The replica failing an operation does not fail it on the primary;
+// the first such error is kept and returned by Err, and the two stores may
+// differ from then on. The replica applies its own replace mode and
+// capacity, so both stores should be configured alike.
+//
+// Events expire on both stores at their ExpireAt, each on its own timer.
+// Until Promote is called, the callbacks of the replica's expiries are
+// suppressed, so every expiry is handled once, by the primary. Events started
+// with a nil atExpire are started on the replica with a nil atExpire too, and
+// go to the replica's expiry handler, which should therefore only be set when
+// promoting it.
+type Replicated[ID comparable, E Event] struct {
+	s, replica Store[ID, E]
+	promoted   atomic.Bool
+
+	mu  sync.Mutex
+	err error
+}
+
+var _ Store[any, Event] = &Replicated[any, Event]{}
+
+// WithReplica wraps s so that its mutating operations are mirrored to replica.
+// replica must not be s, nor be used directly until promoted.
+func WithReplica[ID comparable, E Event](s, replica Store[ID, E]) *Replicated[ID, E] {
+	return &Replicated[ID, E]{s: s, replica: replica}
+}
+
+// Start starts the event in the primary store and, if that succeeds, in the
+// replica with the same ExpireAt. There, atExpire is only called once the
+// replica was promoted.
+func (r *Replicated[ID, E]) Start(id ID, event E, atExpire func()) error {
+	if err := r.s.Start(id, event, atExpire); err != nil {
+		return err
+	}
+
+	standby := atExpire
+	if atExpire != nil {
+		standby = func() {
+			if r.promoted.Load() {
+				atExpire()
+			}
+		}
+	}
+
+	if err := r.replica.Start(id, event, standby); err != nil {
+		r.fail(fmt.Errorf("timerstore: replica start: %w", err))
+	}
+
+	return nil
+}
+
+// Cancel cancels the event in the primary store and, if it was present, in
+// the replica.
+func (r *Replicated[ID, E]) Cancel(id ID) (E, bool) {
+	event, ok := r.s.Cancel(id)
+	if ok {
+		r.replica.Cancel(id)
+	}
+
+	return event, ok
+}
+
+// Reschedule moves the deadline of the event in the primary store and, if it
+// was present, in the replica. The primary must be a Rescheduler; if it is
+// not, Reschedule reports the event absent. A replica that is not a
+// Rescheduler keeps the old deadline, and the failure is reported by Err.
+func (r *Replicated[ID, E]) Reschedule(id ID, at time.Time) (E, bool) {
+	s, ok := r.s.(Rescheduler[ID, E])
+	if !ok {
+		var zeroE E
+		return zeroE, false
+	}
+
+	event, ok := s.Reschedule(id, at)
+	if !ok {
+		return event, false
+	}
+
+	if rs, isRescheduler := r.replica.(Rescheduler[ID, E]); isRescheduler {
+		rs.Reschedule(id, at)
+	} else {
+		r.fail(errReplicaReschedule)
+	}
+
+	return event, true
+}
+
+// Promote lets the replica call the callbacks of the events it expires from
+// now on, for it to take over from a failed primary. Operations made through
+// r after Promote are still mirrored; once the primary is gone, use the
+// replica directly instead.
+func (r *Replicated[ID, E]) Promote() { r.promoted.Store(true) }
+
+// Err returns the first error met while mirroring to the replica, if any.
+func (r *Replicated[ID, E]) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.err
+}
+
+func (r *Replicated[ID, E]) fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err == nil {
+		r.err = err
+	}
+}
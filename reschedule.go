@@ -0,0 +1,313 @@
+package timerstore
+
+import "time"
+
+// ExpirySetter is implemented by events whose expiration time can be updated.
+// When a method such as Reschedule changes the deadline of such an event, it
+// calls SetExpireAt so the stored event keeps agreeing with its timer. Events
+// held by value rather than by pointer cannot be updated this way.
+type ExpirySetter interface {
+	SetExpireAt(t time.Time)
+}
+
+// Reschedule moves the deadline of the event stored for id to at, resetting
+// its timer. It returns the event and whether it was present. If the event
+// implements ExpirySetter, its ExpireAt is updated too.
+//
+// Reschedule never loses an event racing with its expiry. It re-arms the
+// existing timer with Reset instead of creating a new one, and does so with
+// the store lock held, which expiry needs to remove the event. Either the
+// event expired first and Reschedule reports it absent, or Reschedule moved
+// the deadline first: if the timer fired meanwhile, expiry then sees the new
+// deadline and arms the timer again instead of removing the event.
+func (s *Simple[ID, E]) Reschedule(id ID, at time.Time) (E, bool) {
+	s.mu.Lock()
+	d, ok := s.m[id]
+	if !ok {
+		s.mu.Unlock()
+		var zeroE E
+		return zeroE, false
+	}
+
+	old, event := d.deadline, d.event
+	s.moveDeadline(d, at)
+	s.mu.Unlock()
+
+	s.rescheduled(id, old, at)
+	return event, true
+}
+
+// Touch moves the deadline of the event stored for id to d from now. It
+// returns the event and whether it was present. See Reschedule.
+func (s *Simple[ID, E]) Touch(id ID, d time.Duration) (E, bool) {
+	return s.Reschedule(id, time.Now().Add(d))
+}
+
+// ScheduleNoLaterThan makes sure the event stored for id fires no later than
+// at: its deadline is moved to at only if at is earlier than the current one,
+// and left alone otherwise. Several "fire by" requests thus coalesce into the
+// earliest. It returns the event and whether it was present. If the
+// deadline is moved and the event implements ExpirySetter, its ExpireAt is
+// updated too.
+func (s *Simple[ID, E]) ScheduleNoLaterThan(id ID, at time.Time) (E, bool) {
+	s.mu.Lock()
+	d, ok := s.m[id]
+	if !ok {
+		s.mu.Unlock()
+		var zeroE E
+		return zeroE, false
+	}
+
+	old, event := d.deadline, d.event
+	if !at.Before(old) {
+		s.mu.Unlock()
+		return event, true
+	}
+
+	s.moveDeadline(d, at)
+	s.mu.Unlock()
+
+	s.rescheduled(id, old, at)
+	return event, true
+}
+
+// CancelWithGrace is a cancellation that still lets the event fire, after
+// grace from now, to give work in flight time to settle: the timer of the
+// event stored for id is reset rather than stopped, moving its deadline to
+// grace from now whether that is sooner or later than the current one. It is
+// Touch without returning the event, and reports whether the event was
+// present. If the event implements ExpirySetter, its ExpireAt is updated
+// too. A grace of zero or less makes the event expire right away.
+func (s *Simple[ID, E]) CancelWithGrace(id ID, grace time.Duration) bool {
+	_, ok := s.Touch(id, grace)
+	return ok
+}
+
+// OnReschedule registers fn to be called whenever the deadline of a stored
+// event changes after it was started: by Reschedule, Touch,
+// ScheduleNoLaterThan, CancelWithGrace or Replace. It is not called by Start,
+// nor when a call leaves the deadline as it was. fn runs on the goroutine
+// that moved the deadline, after the store lock is released, so it may call
+// the store; calls for the same id racing with each other may be reported in
+// either order. Passing nil removes the hook.
+func (s *Simple[ID, E]) OnReschedule(fn func(id ID, oldExpiry, newExpiry time.Time)) {
+	if fn == nil {
+		s.onReschedule.Store(nil)
+		return
+	}
+
+	s.onReschedule.Store(&fn)
+}
+
+// rescheduled calls the OnReschedule hook, if any, for a deadline moved from
+// old to new. It must be called without s.mu held.
+func (s *Simple[ID, E]) rescheduled(id ID, old, new time.Time) {
+	if fn := s.onReschedule.Load(); fn != nil && !old.Equal(new) {
+		(*fn)(id, old, new)
+	}
+}
+
+// moveDeadline sets the deadline of d and re-arms its timer, honouring
+// WithMinRearmInterval. It must be called with s.mu held.
+func (s *Simple[ID, E]) moveDeadline(d *data[E], deadline time.Time) {
+	d.deadline = deadline
+	if setter, ok := any(d.event).(ExpirySetter); ok {
+		setter.SetExpireAt(deadline)
+	}
+	s.repromote(d)
+
+	now, at := time.Now(), deadline
+	if d.suspended {
+		d.remaining = deadline.Sub(now)
+		return
+	}
+
+	if iv := s.opts.minRearm; iv > 0 && now.Sub(d.resetAt) < iv {
+		if !deadline.Before(d.armedAt) {
+			// expire re-arms for the later deadline when the timer fires.
+			return
+		}
+
+		if at = maxTime(deadline, d.resetAt.Add(iv)); !at.Before(d.armedAt) {
+			return
+		}
+	}
+
+	if !s.stop(d) {
+		// The timer already fired and expire is waiting for the lock. It
+		// will see the new deadline and re-arm if it is still in the future.
+		return
+	}
+
+	s.reset(d, now, at)
+}
+
+// reset arms the stopped or fired timer of d to fire at at. It must be called
+// with s.mu held.
+func (s *Simple[ID, E]) reset(d *data[E], now, at time.Time) {
+	wait := s.coarsen(now, s.delay(now, at))
+	d.armedAt, d.resetAt = now.Add(wait), now
+	s.armed.Add(1)
+	d.timer.Reset(wait)
+}
+
+// repromote re-arms the promote timer of d, if it is a lazily persisted event
+// not yet in the DB, to fire when its deadline comes within the lazy persist
+// window. It must be called with s.mu held.
+func (s *Simple[ID, E]) repromote(d *data[E]) {
+	if d.promote != nil {
+		d.promote.Reset(time.Until(d.deadline.Add(-s.opts.lazyWindow)))
+	}
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
+
+// delay returns how long to arm a timer for an event due at at. With drift
+// correction the remaining time is measured on the wall clock, ignoring the
+// monotonic readings, and capped at the check interval.
+func (s *Simple[ID, E]) delay(now, at time.Time) time.Duration {
+	iv := s.opts.driftCheck
+	if iv <= 0 {
+		return at.Sub(now)
+	}
+
+	return min(at.Round(0).Sub(now.Round(0)), iv)
+}
+
+// OnReschedule registers fn to be called whenever the deadline of an event
+// stored in memory changes after it was started, such as by Replace. See
+// Simple.OnReschedule.
+func (p *Persistent[ID, E]) OnReschedule(fn func(id ID, oldExpiry, newExpiry time.Time)) {
+	p.s.OnReschedule(fn)
+}
+
+// RescheduleMany moves the deadline of every listed event by shiftBy, which
+// may be negative, under one acquisition of the store lock. It reports for
+// every listed id whether it was present; absent ids are skipped. Events
+// implementing ExpirySetter get their ExpireAt updated too. See Reschedule.
+func (s *Simple[ID, E]) RescheduleMany(ids []ID, shiftBy time.Duration) map[ID]bool {
+	present, _ := s.rescheduleMany(ids, shiftBy)
+	return present
+}
+
+// rescheduleMany is RescheduleMany, also returning the moved events.
+func (s *Simple[ID, E]) rescheduleMany(ids []ID, shiftBy time.Duration) (map[ID]bool, []Entry[ID, E]) {
+	type moved struct {
+		id      ID
+		old, at time.Time
+	}
+
+	present := make(map[ID]bool, len(ids))
+	var changes []moved
+	var entries []Entry[ID, E]
+
+	s.mu.Lock()
+	for _, id := range ids {
+		d, ok := s.m[id]
+		present[id] = ok
+		if !ok {
+			continue
+		}
+
+		old := d.deadline
+		s.moveDeadline(d, old.Add(shiftBy))
+		changes = append(changes, moved{id, old, d.deadline})
+		// A lazily persisted event is put with its new deadline when
+		// promoted.
+		if d.promote == nil {
+			entries = append(entries, Entry[ID, E]{ID: id, Event: d.event})
+		}
+	}
+	s.mu.Unlock()
+
+	for _, c := range changes {
+		s.rescheduled(c.id, c.old, c.at)
+	}
+
+	return present, entries
+}
+
+var _ Rescheduler[any, Event] = &Persistent[any, Event]{}
+
+// Reschedule moves the deadline of the event stored for id to at, as
+// Simple.Reschedule does in memory, and puts the moved event to the DB if it
+// implements ExpirySetter, so that a recovered event keeps the new deadline;
+// for other events the DB keeps the old one. With WithReadThrough, an event
+// not in memory is loaded from the DB first, as by Load. It reports the event
+// absent if it is not stored, or if loading it fails; use RescheduleChecked
+// to get the error.
+func (p *Persistent[ID, E]) Reschedule(id ID, at time.Time) (E, bool) {
+	event, ok, _ := p.RescheduleChecked(id, at)
+	return event, ok
+}
+
+// RescheduleChecked is like Reschedule, but also returns the error of loading
+// the event or of putting it to the DB. If putting fails, the deadline stays
+// moved in memory and the DB keeps the old one. A Reschedule racing with the
+// expiry or cancellation of the event may put it back to the DB after it was
+// deleted, for the next Recover to start it again.
+func (p *Persistent[ID, E]) RescheduleChecked(id ID, at time.Time) (E, bool, error) {
+	var zeroE E
+	if p.opts.readThrough {
+		if _, ok, err := p.Load(id); !ok {
+			return zeroE, false, err
+		}
+	}
+
+	p.s.mu.Lock()
+	d, ok := p.s.m[id]
+	if !ok {
+		p.s.mu.Unlock()
+		return zeroE, false, nil
+	}
+
+	old, event := d.deadline, d.event
+	p.s.moveDeadline(d, at)
+	// A lazily persisted event is put with its new deadline when promoted.
+	lazy := d.promote != nil
+	p.s.mu.Unlock()
+
+	p.s.rescheduled(id, old, at)
+	if _, isSetter := any(event).(ExpirySetter); !isSetter || lazy {
+		return event, true, nil
+	}
+
+	return event, true, p.db.Put(id, event)
+}
+
+// Touch moves the deadline of the event stored for id to d from now. See
+// Persistent.Reschedule.
+func (p *Persistent[ID, E]) Touch(id ID, d time.Duration) (E, bool) {
+	return p.Reschedule(id, time.Now().Add(d))
+}
+
+// RescheduleMany moves the deadline of every listed event stored in memory by
+// shiftBy, as Simple.RescheduleMany does, then puts the moved events to the
+// DB, in one PutBatch if the DB is a BatchPutter. Only events implementing
+// ExpirySetter carry the new deadline into the DB. If putting fails, the
+// deadlines stay moved in memory, the DB keeps the old ones and the error is
+// returned.
+func (p *Persistent[ID, E]) RescheduleMany(ids []ID, shiftBy time.Duration) (map[ID]bool, error) {
+	present, entries := p.s.rescheduleMany(ids, shiftBy)
+	if len(entries) == 0 {
+		return present, nil
+	}
+
+	if b, ok := p.db.(BatchPutter[ID, E]); ok {
+		return present, b.PutBatch(entries)
+	}
+
+	for _, e := range entries {
+		if err := p.db.Put(e.ID, e.Event); err != nil {
+			return present, err
+		}
+	}
+
+	return present, nil
+}
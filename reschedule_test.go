@@ -0,0 +1,126 @@
+package timerstore
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestRescheduleRacingExpiry moves deadlines of events while they are due, to
+// be run with -race: every event must fire exactly once, however the
+// Reschedule calls interleave with its expiry.
+func TestRescheduleRacingExpiry(t *testing.T) {
+	s := NewSimple[string, testEvent]()
+	defer s.Close()
+
+	const workers, rounds = 8, 200
+	counts := make([][rounds]atomic.Int32, workers)
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range rounds {
+				id := fmt.Sprint(w, "-", r)
+				fired := make(chan struct{})
+				err := s.Start(id, testEvent{At: time.Now().Add(50 * time.Microsecond)}, func() {
+					if counts[w][r].Add(1) == 1 {
+						close(fired)
+					}
+				})
+				if err != nil {
+					t.Errorf("Start(%q): %v", id, err)
+					return
+				}
+
+				for range 5 {
+					at := time.Now().Add(rand.N(100 * time.Microsecond))
+					if _, ok := s.Reschedule(id, at); !ok {
+						break
+					}
+				}
+
+				select {
+				case <-fired:
+				case <-time.After(5 * time.Second):
+					t.Errorf("event %q lost", id)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Give a duplicate expiry the time to show up.
+	time.Sleep(10 * time.Millisecond)
+	for w := range counts {
+		for r := range counts[w] {
+			if n := counts[w][r].Load(); n != 1 {
+				t.Errorf("event %d-%d fired %d times; want 1", w, r, n)
+			}
+		}
+	}
+	if err := s.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+	if st := s.Stats(); st.Expired != workers*rounds || st.Len != 0 {
+		t.Errorf("Stats = %+v; want %d expired and none left", st, workers*rounds)
+	}
+}
+
+// movableEvent is an event whose deadline Reschedule can update.
+type movableEvent struct{ At time.Time }
+
+func (e *movableEvent) ExpireAt() time.Time      { return e.At }
+func (e *movableEvent) SetExpireAt(at time.Time) { e.At = at }
+
+func TestPersistentReschedule(t *testing.T) {
+	db := newMapDB[*movableEvent]()
+	p := NewPersistentStore[string, *movableEvent](db, WithReadThrough())
+	defer p.Close()
+
+	now := time.Now()
+	at := now.Add(2 * time.Hour)
+	if err := p.Start("a", &movableEvent{At: now.Add(time.Hour)}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, ok := p.Reschedule("a", at); !ok {
+		t.Fatal("Reschedule(a) found no event")
+	}
+	if got, _ := db.Get("a"); !got.At.Equal(at) {
+		t.Errorf("DB holds deadline %v; want %v", got.At, at)
+	}
+
+	// Only in the DB, loaded on the miss.
+	db.Put("b", &movableEvent{At: now.Add(time.Hour)})
+	if _, ok := p.Reschedule("b", at); !ok {
+		t.Fatal("Reschedule(b) did not read through")
+	}
+	if got, ok := p.s.peek("b"); !ok || !got.At.Equal(at) {
+		t.Errorf("in memory: %v, %v; want deadline %v", got, ok, at)
+	}
+
+	if _, ok := p.Reschedule("c", at); ok {
+		t.Error("Reschedule(c) reported an event neither stored nor in the DB")
+	}
+}
+
+func TestReplicatedPersistentReschedule(t *testing.T) {
+	primary := NewPersistentStore[string, *movableEvent](newMapDB[*movableEvent]())
+	defer primary.Close()
+	replica := NewPersistentStore[string, *movableEvent](newMapDB[*movableEvent]())
+	defer replica.Close()
+
+	r := WithReplica[string, *movableEvent](primary, replica)
+	if err := r.Start("a", &movableEvent{At: time.Now().Add(time.Hour)}, func() {}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, ok := r.Reschedule("a", time.Now().Add(2*time.Hour)); !ok {
+		t.Error("Reschedule over Persistent reported the event absent")
+	}
+	if err := r.Err(); err != nil {
+		t.Errorf("Err = %v; want nil", err)
+	}
+}
@@ -0,0 +1,88 @@
+package timerstore
+
+import (
+	"math"
+	"time"
+)
+
+// Backoff describes an exponential backoff: the n-th retry, counting from
+// zero, waits Base * Factor^n, capped at Max. MaxAttempts bounds the total
+// number of attempts, including the first; zero means no bound.
+type Backoff struct {
+	Base        time.Duration
+	Factor      float64
+	Max         time.Duration
+	MaxAttempts int
+}
+
+// delay returns the delay before the given retry, starting at zero.
+func (b Backoff) delay(retry int) time.Duration {
+	d := float64(b.Base) * math.Pow(b.Factor, float64(retry))
+	if b.Max > 0 && d > float64(b.Max) {
+		return b.Max
+	}
+
+	return time.Duration(d)
+}
+
+type retryEvent struct {
+	at      time.Time
+	attempt int
+}
+
+func (e retryEvent) ExpireAt() time.Time { return e.at }
+
+// RetryScheduler runs functions on a timer and retries the failing ones with
+// exponential backoff. It is safe for concurrent use.
+type RetryScheduler[ID comparable] struct {
+	s      Simple[ID, retryEvent]
+	policy Backoff
+	onDone func(id ID, err error)
+}
+
+// NewRetryScheduler creates a RetryScheduler retrying with policy. onDone, if
+// not nil, is called once per scheduled id when its function succeeds, with a
+// nil error, or when the attempts are exhausted, with the last error.
+func NewRetryScheduler[ID comparable](policy Backoff, onDone func(id ID, err error)) *RetryScheduler[ID] {
+	return &RetryScheduler[ID]{policy: policy, onDone: onDone}
+}
+
+// Schedule runs fn after delay. If fn returns an error, it is run again after
+// the next backoff step, until it succeeds or the attempts are exhausted.
+// Scheduling an id that is already scheduled replaces it.
+func (r *RetryScheduler[ID]) Schedule(id ID, delay time.Duration, fn func() error) error {
+	return r.arm(id, retryEvent{at: time.Now().Add(delay)}, fn)
+}
+
+func (r *RetryScheduler[ID]) arm(id ID, e retryEvent, fn func() error) error {
+	return r.s.Start(id, e, func() {
+		err := fn()
+		if err != nil && (r.policy.MaxAttempts <= 0 || e.attempt+1 < r.policy.MaxAttempts) {
+			next := retryEvent{at: time.Now().Add(r.policy.delay(e.attempt)), attempt: e.attempt + 1}
+			if r.arm(id, next, fn) == nil {
+				return
+			}
+		}
+
+		if r.onDone != nil {
+			r.onDone(id, err)
+		}
+	})
+}
+
+// Cancel stops retrying id without calling onDone, and reports whether it was
+// scheduled.
+func (r *RetryScheduler[ID]) Cancel(id ID) bool {
+	_, ok := r.s.Cancel(id)
+	return ok
+}
+
+// Attempts returns the number of attempts already made for id and whether it
+// is scheduled.
+func (r *RetryScheduler[ID]) Attempts(id ID) (int, bool) {
+	e, ok := r.s.Get(id)
+	return e.attempt, ok
+}
+
+// Len returns the number of ids scheduled.
+func (r *RetryScheduler[ID]) Len() int { return r.s.Len() }
@@ -0,0 +1,43 @@
+package timerstore
+
+// Schedule stores the event with a timer that only removes it when it
+// expires, for using the store as a set of ids valid until their TTL runs
+// out, checked with Get. No callback runs on expiry, not even the handler set
+// with SetExpiryHandler, and no closure is allocated for one. The event
+// otherwise behaves like one started with Start: Cancel, Reschedule and the
+// replace mode work the same, and it counts as expired when it leaves the
+// store. It does not coalesce with other events.
+func (s *Simple[ID, E]) Schedule(id ID, event E) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return kept(s.schedule(id, event))
+}
+
+// schedule adds an event without a callback. It must be called with s.mu
+// held.
+func (s *Simple[ID, E]) schedule(id ID, event E) error {
+	d, err := s.add(id, event, event.ExpireAt(), nil)
+	if err != nil {
+		return err
+	}
+	d.silent = true
+
+	return nil
+}
+
+// Schedule stores the event in the persistent storage and in memory with a
+// timer that only removes it, from memory and the DB, when it expires.
+// Events recovered from the DB lose this and go to the default handler. See
+// Simple.Schedule.
+func (p *Persistent[ID, E]) Schedule(id ID, event E) error {
+	if err := p.put(id, event); err != nil {
+		return kept(err)
+	}
+
+	p.s.mu.Lock()
+	err := p.s.schedule(id, event)
+	p.s.mu.Unlock()
+
+	return p.rollback(id, event, err)
+}
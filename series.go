@@ -0,0 +1,61 @@
+package timerstore
+
+import (
+	"errors"
+	"slices"
+)
+
+// ErrEmptySeries is returned by StartSeries for a series without events.
+var ErrEmptySeries = errors.New("timerstore: empty series")
+
+// series holds the stages of an event started with StartSeries, sorted by
+// ExpireAt. It is guarded by the store lock.
+type series[E Event] struct {
+	events []E
+	stages []int // index of each event in the slice given to StartSeries
+	next   int   // stage currently armed
+	fn     func(stage int, event E)
+}
+
+// StartSeries stores a sequence of staged events, such as warn, escalate and
+// expire, under one id. The events fire in ExpireAt order, each calling
+// atExpire with its index in events as the stage and the event itself. The id
+// stays in the store until the last stage fires, with Get returning the stage
+// currently armed, and Cancel stops the whole series.
+//
+// events need not be sorted: they are ordered by ExpireAt, ties keeping their
+// order in events, so a stage index is not necessarily one more than the
+// previous. A stage already past due when the previous one fires fires right
+// after it. Only the last stage is an expiry: it goes through the dispatch
+// mode and the expiry rate limit and counts as expired, while earlier stages
+// call atExpire on the timer goroutine. The weight of the series is that of
+// its first stage. Starting an id that is already present replaces it as
+// Start does.
+func (s *Simple[ID, E]) StartSeries(id ID, events []E, atExpire func(stage int, event E)) error {
+	if len(events) == 0 {
+		return ErrEmptySeries
+	}
+
+	sr := &series[E]{events: slices.Clone(events), stages: make([]int, len(events)), fn: atExpire}
+	for i := range sr.stages {
+		sr.stages[i] = i
+	}
+	slices.SortStableFunc(sr.stages, func(a, b int) int { return events[a].ExpireAt().Compare(events[b].ExpireAt()) })
+	for i, stage := range sr.stages {
+		sr.events[i] = events[stage]
+	}
+
+	last := len(events) - 1
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.add(id, sr.events[0], sr.events[0].ExpireAt(), func() {
+		atExpire(sr.stages[last], sr.events[last])
+	})
+	if err != nil {
+		return kept(err)
+	}
+	d.series = sr
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package timerstore
+
+import (
+	"sync"
+	"time"
+)
+
+var _ Store[any, Event] = &Small[any, Event]{}
+
+// Small is a Store for sets that rarely hold more than a handful of events,
+// such as the timeouts of a single connection. It keeps the events in a
+// slice sorted by ExpireAt, found and inserted by linear scans, and arms one
+// timer for the earliest of them, where Simple keeps a map and a timer per
+// event. Start and Cancel cost O(n), which for small n beats the hashing and
+// timer management of Simple; for more than a few dozen events, use Simple.
+//
+// Small offers none of the options of Simple. Like Simple, it removes an
+// event before calling its callback, so the callback may start the same id
+// again. Callbacks run one after another on the timer goroutine, in
+// deadline order. The zero value is ready to use.
+type Small[ID comparable, E Event] struct {
+	mu     sync.Mutex
+	events []smallEvent[ID, E]
+	timer  *time.Timer
+	next   time.Time // deadline the timer is armed for, zero if stopped
+}
+
+type smallEvent[ID comparable, E Event] struct {
+	id       ID
+	event    E
+	atExpire func()
+}
+
+// Start stores the event and arms the timer to call atExpire when it
+// expires. Starting an id that is already present replaces the previous
+// event.
+func (s *Small[ID, E]) Start(id ID, event E, atExpire func()) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(id)
+	at := event.ExpireAt()
+	i := len(s.events)
+	for i > 0 && at.Before(s.events[i-1].event.ExpireAt()) {
+		i--
+	}
+	s.events = append(s.events, smallEvent[ID, E]{})
+	copy(s.events[i+1:], s.events[i:])
+	s.events[i] = smallEvent[ID, E]{id, event, atExpire}
+	s.arm()
+
+	return nil
+}
+
+// Cancel removes the event stored for id and re-arms the timer if needed. It
+// returns the event and whether it was present.
+func (s *Small[ID, E]) Cancel(id ID) (E, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.remove(id)
+	if ok {
+		s.arm()
+	}
+
+	return e.event, ok
+}
+
+// Get returns the event stored for id and whether it is present.
+func (s *Small[ID, E]) Get(id ID) (E, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.events {
+		if e.id == id {
+			return e.event, true
+		}
+	}
+
+	var zeroE E
+	return zeroE, false
+}
+
+// Len returns the number of events stored.
+func (s *Small[ID, E]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.events)
+}
+
+// remove deletes the event of id from the slice. It must be called with s.mu
+// held.
+func (s *Small[ID, E]) remove(id ID) (smallEvent[ID, E], bool) {
+	for i, e := range s.events {
+		if e.id == id {
+			s.events = append(s.events[:i], s.events[i+1:]...)
+			clear(s.events[len(s.events) : len(s.events)+1])
+			return e, true
+		}
+	}
+
+	return smallEvent[ID, E]{}, false
+}
+
+// arm makes the timer fire at the earliest deadline, or stops it if there is
+// no event. It must be called with s.mu held.
+func (s *Small[ID, E]) arm() {
+	if len(s.events) == 0 {
+		if s.timer != nil {
+			s.timer.Stop()
+		}
+		s.next = time.Time{}
+		return
+	}
+
+	at := s.events[0].event.ExpireAt()
+	if at.Equal(s.next) {
+		return
+	}
+
+	s.next = at
+	if s.timer == nil {
+		s.timer = time.AfterFunc(time.Until(at), s.expire)
+		return
+	}
+
+	s.timer.Stop()
+	s.timer.Reset(time.Until(at))
+}
+
+// expire removes the events that are due and calls their callbacks in order.
+func (s *Small[ID, E]) expire() {
+	s.mu.Lock()
+	now := time.Now()
+	n := 0
+	for n < len(s.events) && !s.events[n].event.ExpireAt().After(now) {
+		n++
+	}
+	due := make([]smallEvent[ID, E], n)
+	copy(due, s.events)
+	s.events = append(s.events[:0], s.events[n:]...)
+	clear(s.events[len(s.events) : len(s.events)+n])
+	s.next = time.Time{}
+	s.arm()
+	s.mu.Unlock()
+
+	for _, e := range due {
+		if e.atExpire != nil {
+			e.atExpire()
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package timerstore
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// benchmarkStartCancel measures a Cancel followed by a Start of the same id
+// in a store holding n events, the steady state of a set of timeouts that are
+// reset as work progresses.
+func benchmarkStartCancel(b *testing.B, s Store[int, testEvent], n int) {
+	at := time.Now().Add(time.Hour)
+	for i := range n {
+		if err := s.Start(i, testEvent{At: at.Add(time.Duration(i))}, func() {}); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		id := i % n
+		s.Cancel(id)
+		if err := s.Start(id, testEvent{At: at.Add(time.Duration(n + i))}, func() {}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkSmall and BenchmarkSimple compare the two stores for the sizes
+// Small is meant for.
+func BenchmarkSmall(b *testing.B) {
+	for _, n := range []int{1, 2, 4, 8, 16, 32} {
+		b.Run(fmt.Sprint("n=", n), func(b *testing.B) {
+			benchmarkStartCancel(b, &Small[int, testEvent]{}, n)
+		})
+	}
+}
+
+func BenchmarkSimple(b *testing.B) {
+	for _, n := range []int{1, 2, 4, 8, 16, 32} {
+		b.Run(fmt.Sprint("n=", n), func(b *testing.B) {
+			s := NewSimple[int, testEvent]()
+			defer s.Close()
+			benchmarkStartCancel(b, s, n)
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package timerstore
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"time"
+)
+
+// KeyCodec encodes ids to bytes and decodes them back. StringKeyCodec and
+// IntKeyCodec cover string and integer ids; other id types, such as structs,
+// need their own.
+type KeyCodec[ID any] interface {
+	EncodeKey(id ID) ([]byte, error)
+	DecodeKey(data []byte) (ID, error)
+}
+
+const (
+	snapshotMagic   = "TSNP"
+	snapshotVersion = 1
+)
+
+// ErrSnapshotVersion is returned by Restore for a snapshot written in a format
+// version it does not understand.
+var ErrSnapshotVersion = errors.New("timerstore: unsupported snapshot version")
+
+// Snapshot writes all events currently stored to w in a compact binary format.
+// The stream starts with a magic string and a format version, followed by one
+// record per event:
+//
+//	uvarint(len(id)) id
+//	varint(unix seconds) uvarint(nanoseconds)
+//	uvarint(len(payload)) payload
+//
+// The id is encoded with keys and the payload with events. The time is the
+// deadline the event's timer is armed for. It is stored as seconds and
+// nanoseconds rather than as Unix nanoseconds so that times outside the
+// int64 nanosecond range, such as the zero time, survive a round-trip.
+func (s *Simple[ID, E]) Snapshot(w io.Writer, keys KeyCodec[ID], events Codec[E]) error {
+	s.mu.Lock()
+	records := make([]entry[ID, E], 0, len(s.m))
+	for id, d := range s.m {
+		records = append(records, entry[ID, E]{id, &data[E]{event: d.event, deadline: d.deadline}})
+	}
+	s.mu.Unlock()
+
+	return writeSnapshot(w, keys, events, records)
+}
+
+// writeSnapshot writes the events and deadlines of records to w in the format
+// of Snapshot.
+func writeSnapshot[ID comparable, E Event](w io.Writer, keys KeyCodec[ID], events Codec[E], records []entry[ID, E]) error {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(snapshotMagic); err != nil {
+		return err
+	}
+	if err := bw.WriteByte(snapshotVersion); err != nil {
+		return err
+	}
+
+	var buf []byte
+	for _, r := range records {
+		key, err := keys.EncodeKey(r.id)
+		if err != nil {
+			return fmt.Errorf("timerstore: encode key: %w", err)
+		}
+
+		payload, err := events.Encode(r.d.event)
+		if err != nil {
+			return fmt.Errorf("timerstore: encode event: %w", err)
+		}
+
+		buf = binary.AppendUvarint(buf[:0], uint64(len(key)))
+		buf = append(buf, key...)
+		buf = binary.AppendVarint(buf, r.d.deadline.Unix())
+		buf = binary.AppendUvarint(buf, uint64(r.d.deadline.Nanosecond()))
+		buf = binary.AppendUvarint(buf, uint64(len(payload)))
+		buf = append(buf, payload...)
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// Restore reads a stream written by Snapshot and starts every event in it,
+// armed for the deadline recorded in the snapshot. atExpire is called with
+// the id and event of each restored event when it expires; events already
+// past their deadline fire immediately. Restore returns the number of events
+// started, which is also valid when an error stops it partway. A snapshot
+// with an unknown format version is rejected with ErrSnapshotVersion before
+// any event is started.
+func (s *Simple[ID, E]) Restore(r io.Reader, keys KeyCodec[ID], events Codec[E], atExpire func(id ID, event E)) (int, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, len(snapshotMagic)+1)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return 0, fmt.Errorf("timerstore: read snapshot header: %w", err)
+	}
+	if string(header[:len(snapshotMagic)]) != snapshotMagic {
+		return 0, errors.New("timerstore: not a snapshot")
+	}
+	if v := header[len(snapshotMagic)]; v != snapshotVersion {
+		return 0, fmt.Errorf("%w %d", ErrSnapshotVersion, v)
+	}
+
+	n := 0
+	for {
+		key, err := readChunk(br, "snapshot")
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, truncated("snapshot", err)
+		}
+
+		sec, err := binary.ReadVarint(br)
+		if err != nil {
+			return n, truncated("snapshot", err)
+		}
+		nsec, err := binary.ReadUvarint(br)
+		if err != nil {
+			return n, truncated("snapshot", err)
+		}
+
+		payload, err := readChunk(br, "snapshot")
+		if err != nil {
+			return n, truncated("snapshot", err)
+		}
+
+		id, err := keys.DecodeKey(key)
+		if err != nil {
+			return n, fmt.Errorf("timerstore: decode key: %w", err)
+		}
+
+		event, err := events.Decode(payload)
+		if err != nil {
+			return n, fmt.Errorf("timerstore: decode event: %w", err)
+		}
+
+		s.mu.Lock()
+		_, err = s.add(id, event, time.Unix(sec, int64(nsec)), func() { atExpire(id, event) })
+		s.mu.Unlock()
+		if err == errKeep {
+			continue
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+}
+
+// chunkStep bounds how far readChunk allocates ahead of the bytes it has
+// read, so that a corrupt length cannot make it allocate far more memory than
+// the stream holds.
+const chunkStep = 64 << 10
+
+// readChunk reads a uvarint length-prefixed byte slice of what, such as
+// "snapshot", for the errors.
+func readChunk(r *bufio.Reader, what string) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > math.MaxInt {
+		return nil, errors.New("timerstore: corrupt " + what + ": chunk too large")
+	}
+
+	n := int(size)
+	b := make([]byte, 0, min(n, chunkStep))
+	for len(b) < n {
+		step := min(n-len(b), chunkStep)
+		b = slices.Grow(b, step)
+		m, err := io.ReadFull(r, b[len(b):len(b)+step])
+		b = b[:len(b)+m]
+		if err != nil {
+			return nil, truncated(what, err)
+		}
+	}
+
+	return b, nil
+}
+
+// truncated turns an EOF in the middle of a record of what into an error.
+func truncated(what string, err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errors.New("timerstore: truncated " + what)
+	}
+
+	return err
+}
@@ -0,0 +1,83 @@
+package timerstore
+
+// State tells where an id is in its life cycle, as reported by State.
+type State int
+
+const (
+	// StateAbsent is reported for an id with no event stored.
+	StateAbsent State = iota
+	// StateScheduled is reported for an id whose event is stored, waiting
+	// to expire.
+	StateScheduled
+	// StateFiring is reported for an id whose expiry callback is running.
+	StateFiring
+)
+
+// String returns the name of the state.
+func (st State) String() string {
+	switch st {
+	case StateAbsent:
+		return "absent"
+	case StateScheduled:
+		return "scheduled"
+	case StateFiring:
+		return "firing"
+	default:
+		return "unknown"
+	}
+}
+
+// State reports whether the callback of an event expired for id is running,
+// or else whether an event is stored for id, so that a caller can hold off
+// starting id again while its callback still runs. Firing takes precedence:
+// an id started again from its own callback is reported firing until the
+// callback returns. Ids coalesced into another event and events waiting
+// after StartAfterEvent count as scheduled.
+//
+// The event still leaves the store before its callback runs, as Start
+// guarantees, so that the callback may start the id again; State tracks the
+// running callbacks separately, from when they are called, with the handler
+// set with SetExpiryHandler or OnPanic included, to when they return. An
+// expired event whose callback is held back, by the expiry rate limit, the
+// worker pool, Pause or WithAfterFuncBatching, is absent until it runs, and
+// batched events are not reported firing while the batch handler runs. The
+// answer may be stale by the time State returns.
+func (s *Simple[ID, E]) State(id ID) State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.firing[id] > 0 {
+		return StateFiring
+	}
+	if _, ok := s.m[id]; ok {
+		return StateScheduled
+	}
+	if _, ok := s.aliases[id]; ok {
+		return StateScheduled
+	}
+	if _, ok := s.pending[id]; ok {
+		return StateScheduled
+	}
+
+	return StateAbsent
+}
+
+// setFiring counts a callback of id as started, with delta 1, or returned,
+// with delta -1.
+func (s *Simple[ID, E]) setFiring(id ID, delta int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n := s.firing[id] + delta; n > 0 {
+		if s.firing == nil {
+			s.firing = make(map[ID]int)
+		}
+		s.firing[id] = n
+	} else {
+		delete(s.firing, id)
+	}
+}
+
+// State reports whether the callback of an event expired for id is running,
+// or else whether an event is stored in memory for id. See Simple.State.
+func (p *Persistent[ID, E]) State(id ID) State { return p.s.State(id) }
@@ -0,0 +1,89 @@
+package timerstore
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestStateFiring checks State from callbacks and from other goroutines while
+// many events expire at once, to be run with -race.
+func TestStateFiring(t *testing.T) {
+	s := NewSimple[string, testEvent]()
+	defer s.Close()
+
+	const n = 50
+	release := make(chan struct{})
+	var running, returned sync.WaitGroup
+	running.Add(n)
+	returned.Add(n)
+	for i := range n {
+		id := fmt.Sprint(i)
+		err := s.Start(id, testEvent{At: time.Now()}, func() {
+			defer returned.Done()
+			if st := s.State(id); st != StateFiring {
+				t.Errorf("State(%q) from its callback = %v; want firing", id, st)
+			}
+			if i%2 == 0 {
+				// Started again, but still firing until the callback returns.
+				s.Start(id, testEvent{At: time.Now().Add(time.Hour)}, func() {})
+				if st := s.State(id); st != StateFiring {
+					t.Errorf("State(%q) after restart = %v; want firing", id, st)
+				}
+			}
+			running.Done()
+			<-release
+		})
+		if err != nil {
+			t.Fatalf("Start(%q): %v", id, err)
+		}
+	}
+
+	stop := make(chan struct{})
+	var poll sync.WaitGroup
+	poll.Add(1)
+	go func() {
+		defer poll.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			for i := range n {
+				s.State(fmt.Sprint(i))
+			}
+		}
+	}()
+
+	running.Wait()
+	for i := range n {
+		if st := s.State(fmt.Sprint(i)); st != StateFiring {
+			t.Errorf("State(%d) while its callback blocks = %v; want firing", i, st)
+		}
+	}
+
+	close(release)
+	returned.Wait()
+	close(stop)
+	poll.Wait()
+
+	// setFiring runs after the callback returns, so wait for it.
+	deadline := time.Now().Add(time.Second)
+	for i := 0; i < n; i++ {
+		want := StateAbsent
+		if i%2 == 0 {
+			want = StateScheduled
+		}
+		st := s.State(fmt.Sprint(i))
+		if st == StateFiring && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+			i--
+			continue
+		}
+		if st != want {
+			t.Errorf("State(%d) after its callback = %v; want %v", i, st, want)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package timerstore
 
 import (
+	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -21,38 +23,464 @@ type Store[ID comparable, E Event] interface {
 }
 
 type data[E Event] struct {
-	event E
-	timer *time.Timer
+	event    E
+	timer    *time.Timer
+	atExpire func()
+
+	seq      uint64    // order of the Start that stored the event
+	started  time.Time // when the event was started
+	deadline time.Time // when the event is due
+	armedAt  time.Time // when the timer is set to fire
+	resetAt  time.Time // when the timer was last armed
+
+	done      chan struct{}   // closed on removal, if not nil
+	ctx       context.Context // of StartBound, if not nil
+	onCtxDone func(E)         // called after ctx cancelled the event
+	outcome   Outcome         // why d was removed
+	weight    int64
+	pinned    bool // never evicted, see Pinner
+	unhook    bool // skip the expiry hook
+	silent    bool // no callback, see Schedule
+
+	suspended bool          // timer stopped by Suspend
+	remaining time.Duration // time left when suspended
+
+	grace   time.Duration // time kept stale after the deadline
+	onStale func()
+	stale   bool
+
+	key string // coalescing key of the group d holds the timer for
+
+	promote   *time.Timer // puts a lazily persisted event not yet in the DB
+	recovered bool        // past due when recovered from the DB
+	series    *series[E]  // remaining stages of StartSeries
+}
+
+// entry pairs an id with its stored data.
+type entry[ID comparable, E Event] struct {
+	id ID
+	d  *data[E]
 }
 
 var _ Store[any, Event] = &Simple[any, Event]{}
 
-// Simple is an in-memory Store implementation using sync. Map for
-// concurrency-safe storage.
-type Simple[ID comparable, E Event] struct{ m sync.Map }
+// Simple is an in-memory Store implementation using a mutex guarded map for
+// concurrency-safe storage. The zero value is ready to use with default
+// options; use NewSimple to configure it.
+type Simple[ID comparable, E Event] struct {
+	mu     sync.Mutex
+	m      map[ID]*data[E]
+	weight int64
+	pinned int    // number of pinned events stored
+	seq    uint64 // of the last event stored
+	armed  atomic.Int64
+
+	started, cancelled, expired atomic.Uint64
+	peak                        atomic.Int64
+	lateSum, lateMax            atomic.Int64
+	lateN                       atomic.Uint64
+	lifetimes                   *histogram
+
+	opts     options
+	evictor  Evictor[ID, E]
+	adaptive adaptive
+	paused   bool
+	backlog  []entry[ID, E] // expired while paused
+	limiter  *limiter
+	batch    batcher[ID, E]
+	pool     *pool
+	closed   bool
+	inflight sync.WaitGroup
+	acks     *ackQueue[ID, E]
+	acksOnce sync.Once
+	errs     expiryErrors[ID]
+
+	groups  map[string]*group[ID]    // by coalescing key
+	aliases map[ID]ID                // coalesced id to the id holding the timer
+	pending map[ID]*dependent[ID, E] // waiting after StartAfterEvent
+	waiting map[ID][]ID              // ids of the events waiting, by the id waited for
+	firing  map[ID]int               // number of callbacks running, by id
+
+	handler      atomic.Pointer[func(id ID, event E)]
+	onReschedule atomic.Pointer[func(id ID, oldExpiry, newExpiry time.Time)]
+	onLate       atomic.Pointer[func(id ID, lateness time.Duration)]
+	onRecovered  atomic.Pointer[func(id ID, scheduledFor, firedAt time.Time)]
+	onPanic      atomic.Pointer[func(id ID, event E, v any)]
+	onBatch      atomic.Pointer[func(batch []Entry[ID, E])]
+	onDedup      atomic.Pointer[deduper[ID, E]]
+	onTimeout    atomic.Pointer[func(id ID)]
+	flushLess    atomic.Pointer[func(a, b ID) bool]
+	// onExpire, if set, runs before the callback of every expired event
+	// that is not unhooked. Persistent uses it to delete the event from the
+	// DB.
+	onExpire func(id ID, event E)
+	// onEvict, if set, runs for every event evicted to make room. Persistent
+	// uses it to delete the event from the DB.
+	onEvict func(id ID, event E)
+}
+
+// NewSimple creates a new Simple store configured with the given options.
+func NewSimple[ID comparable, E Event](opts ...Option) *Simple[ID, E] {
+	s := &Simple[ID, E]{}
+	s.init(newOptions(opts))
+	return s
+}
+
+func (s *Simple[ID, E]) init(o options) {
+	s.opts = o
+	if o.dispatch == DispatchPool {
+		s.pool = newPool(o.poolSize)
+	}
+	if o.expiryRate > 0 {
+		s.limiter = newLimiter(o.expiryRate, s.release)
+	}
+	if len(o.lifetimes) > 0 {
+		s.lifetimes = newHistogram(o.lifetimes)
+	}
+}
 
 // Start stores the event and sets a timer to call atExpire when the event
-// expires. It uses time. AfterFunc to schedule the expiration.
+// expires. It uses time.AfterFunc to schedule the expiration. Starting an id
+// that is already present replaces the previous event and stops its timer,
+// unless the replace mode says otherwise (see WithReplaceMode). When the
+// stored event is kept, Start does nothing and returns nil. If atExpire is
+// nil, the expiry goes to the handler set with SetExpiryHandler, if any.
+//
+// The event is always removed from the store before atExpire runs, so
+// atExpire may call Start with the same id to re-arm it. The new event is
+// never clobbered by the cleanup of the one that just fired.
+//
+// Events implementing Coalescer merge with the stored event sharing their
+// key; see Coalescer.
 func (s *Simple[ID, E]) Start(id ID, event E, atExpire func()) error {
-	s.m.Store(id, &data[E]{
-		event: event,
-		timer: time.AfterFunc(time.Until(event.ExpireAt()), func() {
-			s.m.Delete(id)
-			atExpire()
-		}),
-	})
+	s.mu.Lock()
+	if holder, old, at, ok := s.coalesce(id, event); ok {
+		s.mu.Unlock()
+		s.rescheduled(holder, old, at)
+		return nil
+	}
+
+	d, err := s.add(id, event, event.ExpireAt(), atExpire)
+	if err == nil {
+		s.group(id, d)
+	}
+	s.mu.Unlock()
+
+	return kept(err)
+}
+
+// start is Start without coalescing.
+func (s *Simple[ID, E]) start(id ID, event E, atExpire func()) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.add(id, event, event.ExpireAt(), atExpire)
+	return kept(err)
+}
+
+// add stores a new entry for id and arms its timer to fire at deadline,
+// replacing any previous entry. It fails if the store does not admit the
+// event, with errKeep if the previous entry is kept. It must be called with
+// s.mu held.
+func (s *Simple[ID, E]) add(id ID, event E, deadline time.Time, atExpire func()) (*data[E], error) {
+	deadline = s.clamp(event, deadline)
+	err := s.admit(id, event, deadline)
+	if err == errEvict {
+		err = s.evict(id, event, deadline)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.m == nil {
+		s.m = make(map[ID]*data[E])
+	}
+
+	if prev, ok := s.m[id]; ok {
+		s.stop(prev)
+		prev.outcome = OutcomeReplaced
+		s.remove(id, prev)
+	}
+	delete(s.aliases, id)
+
+	now := time.Now()
+	s.countStart(now)
+	wait := s.coarsen(now, s.delay(now, deadline))
+	s.seq++
+	d := &data[E]{
+		event:    event,
+		atExpire: atExpire,
+		seq:      s.seq,
+		started:  now,
+		deadline: deadline,
+		armedAt:  now.Add(wait),
+		resetAt:  now,
+		weight:   weightOf(event),
+		pinned:   pinnedOf(event),
+	}
+	s.armed.Add(1)
+	seq := d.seq
+	d.timer = time.AfterFunc(wait, func() { s.expire(id, d, seq) })
+	s.m[id] = d
+	s.weight += d.weight
+	if d.pinned {
+		s.pinned++
+	}
+	s.started.Add(1)
+	if n := int64(len(s.m)); n > s.peak.Load() {
+		s.peak.Store(n)
+	}
+
+	return d, nil
+}
+
+// startUnhooked is Start for an event whose expiry skips the expiry hook.
+func (s *Simple[ID, E]) startUnhooked(id ID, event E, atExpire func()) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.add(id, event, event.ExpireAt(), atExpire)
+	if err != nil {
+		return kept(err)
+	}
+	d.unhook = true
 
 	return nil
 }
 
+// remove deletes the entry d of id from the store. It must be called with s.mu
+// held.
+func (s *Simple[ID, E]) remove(id ID, d *data[E]) {
+	delete(s.m, id)
+	s.weight -= d.weight
+	if d.pinned {
+		s.pinned--
+	}
+	if d.outcome == OutcomePending {
+		d.outcome = OutcomeCancelled
+	}
+	if d.done != nil {
+		close(d.done)
+	}
+	if d.key != "" {
+		s.ungroup(id, d)
+	}
+	if d.promote != nil {
+		d.promote.Stop()
+	}
+	if s.lifetimes != nil {
+		s.lifetimes.observe(time.Since(d.started))
+	}
+	if d.outcome != OutcomeReplaced && len(s.waiting) > 0 {
+		s.follow(id, d.outcome)
+	}
+}
+
+// expire is run by the timer of d. It removes d from the store, unless it was
+// cancelled or replaced after the timer fired, and dispatches its callback.
+// Removal is by identity and completes before the callback is dispatched, so
+// an event started with the same id from within the callback is left alone.
+// seq is that of d when the timer was created: an event that Transfer took
+// and put back gets a new timer and seq, so the old timer firing late leaves
+// it alone too.
+func (s *Simple[ID, E]) expire(id ID, d *data[E], seq uint64) {
+	s.armed.Add(-1)
+
+	s.mu.Lock()
+	if s.m[id] != d || d.seq != seq {
+		s.mu.Unlock()
+		return
+	}
+	if d.suspended {
+		// Suspend could not stop the timer before it fired.
+		s.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	if s.delay(now, d.deadline) > 0 {
+		// The deadline moved while the timer was armed for an earlier one,
+		// or drift correction armed it for an intermediate check.
+		s.reset(d, now, d.deadline)
+		s.mu.Unlock()
+		return
+	}
+	if sr := d.series; sr != nil && sr.next < len(sr.events)-1 {
+		// An intermediate stage of StartSeries: move on to the next one.
+		stage, event := sr.stages[sr.next], sr.events[sr.next]
+		sr.next++
+		d.event = sr.events[sr.next]
+		d.deadline = d.event.ExpireAt()
+		s.reset(d, now, d.deadline)
+		s.inflight.Add(1)
+		s.mu.Unlock()
+
+		defer s.inflight.Done()
+		sr.fn(stage, event)
+		return
+	}
+	if d.grace > 0 {
+		// The soft deadline of StartWithGrace: keep the event, stale,
+		// until the grace period is over.
+		d.deadline, d.grace, d.stale = d.deadline.Add(d.grace), 0, true
+		s.reset(d, now, d.deadline)
+		s.inflight.Add(1)
+		s.mu.Unlock()
+
+		defer s.inflight.Done()
+		if d.onStale != nil {
+			d.onStale()
+		}
+		return
+	}
+	d.outcome = OutcomeExpired
+	s.remove(id, d)
+	paused := s.paused
+	if paused {
+		s.backlog = append(s.backlog, entry[ID, E]{id, d})
+	} else {
+		s.inflight.Add(1)
+	}
+	s.mu.Unlock()
+
+	s.expired.Add(1)
+	s.late(id, now.Sub(d.deadline))
+	if !paused {
+		s.dispatch(id, d)
+	}
+}
+
+// ExpireWhere expires every stored event for which match returns true right
+// away, as if its deadline had passed: it is removed and its callback is
+// dispatched as for a natural expiry, counting as expired. It returns the
+// number of events expired. match is called for every stored event while the
+// store lock is held, so it must not call the store; the callbacks are
+// dispatched after the lock is released, or held back while the store is
+// paused. An event whose timer fires at the same moment is expired once only.
+func (s *Simple[ID, E]) ExpireWhere(match func(id ID, event E) bool) int {
+	s.mu.Lock()
+	var due []entry[ID, E]
+	for id, d := range s.m {
+		if match(id, d.event) {
+			due = append(due, entry[ID, E]{id, d})
+		}
+	}
+	for _, e := range due {
+		// If the timer already fired, expire finds the entry gone.
+		s.stop(e.d)
+		e.d.outcome = OutcomeExpired
+		s.remove(e.id, e.d)
+	}
+	paused := s.paused
+	if paused {
+		s.backlog = append(s.backlog, due...)
+	} else {
+		s.inflight.Add(len(due))
+	}
+	s.mu.Unlock()
+
+	s.expired.Add(uint64(len(due)))
+	if !paused {
+		for _, e := range due {
+			s.dispatch(e.id, e.d)
+		}
+	}
+
+	return len(due)
+}
+
+// dispatch fires an expired event, subject to the expiry rate limit. The
+// caller must have added d to s.inflight while holding s.mu, so that Close
+// can wait for it.
+func (s *Simple[ID, E]) dispatch(id ID, d *data[E]) {
+	fn := func() {
+		defer s.inflight.Done()
+		s.fire(id, d)
+	}
+
+	if s.limiter != nil {
+		s.limiter.submit(d.event.ExpireAt(), fn)
+		return
+	}
+
+	switch s.opts.dispatch {
+	case DispatchGoroutine:
+		go fn()
+	case DispatchPool:
+		s.pool.submit(fn)
+	default:
+		fn()
+	}
+}
+
+// release runs a callback released by the expiry rate limiter: on the worker
+// pool in DispatchPool mode, and on a goroutine of its own otherwise.
+func (s *Simple[ID, E]) release(fn func()) {
+	if s.pool != nil {
+		s.pool.submit(fn)
+		return
+	}
+
+	go fn()
+}
+
+// fire runs the expiry hook and the callback of an expired event. An event
+// without a callback is handed to the default handler, if any, and is
+// otherwise just removed.
+func (s *Simple[ID, E]) fire(id ID, d *data[E]) {
+	if s.onExpire != nil && !d.unhook {
+		s.onExpire(id, d.event)
+	}
+	if d.recovered {
+		if fn := s.onRecovered.Load(); fn != nil {
+			(*fn)(id, d.deadline, time.Now())
+		}
+	}
+
+	if d.silent {
+		return
+	}
+
+	s.setFiring(id, 1)
+	defer s.setFiring(id, -1)
+	if d.atExpire != nil {
+		s.call(id, d.event, d.atExpire)
+	} else if s.batched(id, d.event) {
+		return
+	} else if h := s.handler.Load(); h != nil {
+		s.call(id, d.event, func() { (*h)(id, d.event) })
+	}
+}
+
+// SetExpiryHandler registers the handler called for expired events started
+// with a nil atExpire, so that all expiries can go through one central
+// handler instead of a closure per Start. A per-Start atExpire always takes
+// precedence and the handler is not called for such events. If both are nil,
+// the event is just removed. The handler is looked up when the event expires,
+// so it may be set or changed at any time. Passing nil removes the handler.
+func (s *Simple[ID, E]) SetExpiryHandler(handler func(id ID, event E)) {
+	if handler == nil {
+		s.handler.Store(nil)
+		return
+	}
+
+	s.handler.Store(&handler)
+}
+
+// DelayedExpiries returns the number of expired events whose callbacks are
+// currently held back by the expiry rate limit (see WithExpiryRateLimit).
+func (s *Simple[ID, E]) DelayedExpiries() int {
+	if s.limiter == nil {
+		return 0
+	}
+
+	return s.limiter.len()
+}
+
 // Cancel stops the timer for the given id and removes the event from the store.
-// It uses sync. Map to safely load and delete the event in a concurrent
-// environment.
+// Cancelling an id coalesced into another event cancels that event, see
+// Coalescer.
 func (s *Simple[ID, E]) Cancel(id ID) (E, bool) {
-	if v, ok := s.m.Load(id); ok {
-		d := v.(*data[E])
-		d.timer.Stop()
-		s.m.Delete(id)
+	if d := s.cancelID(id); d != nil {
 		return d.event, true
 	}
 
@@ -60,6 +488,162 @@ func (s *Simple[ID, E]) Cancel(id ID) (E, bool) {
 	return zeroE, false
 }
 
+// CancelWithHandler is like Cancel, but also returns the callback the event
+// was started with, so that the whole scheduling unit can be handed over
+// elsewhere. The callback has not been called, and never will be by the
+// store: running or discarding it is up to the caller. It is nil for events
+// started without one.
+func (s *Simple[ID, E]) CancelWithHandler(id ID) (E, func(), bool) {
+	if d := s.cancelID(id); d != nil {
+		return d.event, d.atExpire, true
+	}
+
+	var zeroE E
+	return zeroE, nil, false
+}
+
+// cancelID cancels the event stored for id, or the one id is coalesced into,
+// and returns its data, or nil if there is none.
+func (s *Simple[ID, E]) cancelID(id ID) *data[E] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.m[id]
+	if !ok {
+		holder, ok := s.aliases[id]
+		if !ok {
+			return s.cancelWaiting(id)
+		}
+		id, d = holder, s.m[holder]
+	}
+
+	s.cancel(id, d)
+	return d
+}
+
+// CancelAll stops every timer and removes every event from the store, and
+// returns the number of events cancelled.
+func (s *Simple[ID, E]) CancelAll() int { return len(s.cancelWhere(nil)) }
+
+// CancelExpiringBetween cancels every event due in the half-open window
+// [from, to): due at or after from and before to. An event is due at its
+// ExpireAt, or at the deadline it was moved to since, by Reschedule for
+// instance. It returns the cancelled events in no particular order. Simple
+// keeps no ordering, so it scans every stored event, holding the store lock.
+// A window with to not after from cancels nothing.
+func (s *Simple[ID, E]) CancelExpiringBetween(from, to time.Time) []E {
+	return eventsOf(s.cancelWhere(between[ID, E](from, to)))
+}
+
+// between returns a cancelWhere matcher for the events due in [from, to).
+func between[ID comparable, E Event](from, to time.Time) func(id ID, d *data[E]) bool {
+	return func(_ ID, d *data[E]) bool {
+		return !d.deadline.Before(from) && d.deadline.Before(to)
+	}
+}
+
+// CancelMatching cancels every event whose id match returns true for, and
+// returns the cancelled events in no particular order. With a struct ID, such
+// as a (tenant, id) pair, it cancels everything scoped to one field. match is
+// called for every stored id while the store lock is held, so it costs O(n)
+// and must not call the store.
+func (s *Simple[ID, E]) CancelMatching(match func(id ID) bool) []E {
+	return eventsOf(s.cancelWhere(func(id ID, _ *data[E]) bool { return match(id) }))
+}
+
+// eventsOf returns the events of entries.
+func eventsOf[ID any, E Event](entries []Entry[ID, E]) []E {
+	events := make([]E, len(entries))
+	for i, e := range entries {
+		events[i] = e.Event
+	}
+
+	return events
+}
+
+// cancelWhere cancels every stored event match returns true for, or every
+// event if match is nil, and returns them.
+func (s *Simple[ID, E]) cancelWhere(match func(id ID, d *data[E]) bool) []Entry[ID, E] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var entries []Entry[ID, E]
+	for id, d := range s.m {
+		if match == nil || match(id, d) {
+			s.cancel(id, d)
+			entries = append(entries, Entry[ID, E]{ID: id, Event: d.event})
+		}
+	}
+
+	return entries
+}
+
+// cancel stops and removes the entry d of id. It must be called with s.mu
+// held.
+func (s *Simple[ID, E]) cancel(id ID, d *data[E]) {
+	s.stop(d)
+	s.remove(id, d)
+	s.cancelled.Add(1)
+}
+
+// Get returns the event stored for id and whether it is present. With
+// WithRefreshOnGet, it also moves the deadline of the event as Touch does.
+func (s *Simple[ID, E]) Get(id ID) (E, bool) {
+	if ttl := s.opts.refreshTTL; ttl > 0 {
+		return s.Touch(id, ttl)
+	}
+
+	return s.peek(id)
+}
+
+// peek is Get without refreshing the event.
+func (s *Simple[ID, E]) peek(id ID) (E, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d, ok := s.m[id]; ok {
+		return d.event, true
+	}
+
+	var zeroE E
+	return zeroE, false
+}
+
+// Len returns the number of events currently stored.
+func (s *Simple[ID, E]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.m)
+}
+
+// ActiveTimers returns the number of timers that are currently armed. For
+// Simple every stored event owns exactly one timer, so this equals Len except
+// for the brief window in which a timer has fired but its callback has not yet
+// removed the event.
+//
+// An armed timer does not hold a goroutine: the runtime keeps it in its own
+// timer heap and only starts a goroutine when it fires, which then runs
+// atExpire. The goroutine cost of the store is therefore bounded by the number
+// of callbacks running at once, not by ActiveTimers. Comparing
+// runtime.NumGoroutine before and after a Start/Cancel cycle should show no
+// growth, and ActiveTimers dropping back to zero confirms every timer was
+// released.
+func (s *Simple[ID, E]) ActiveTimers() int {
+	return int(s.armed.Load())
+}
+
+// stop stops the timer of d and keeps the armed timer count in sync. It
+// reports whether the timer was stopped before it fired.
+func (s *Simple[ID, E]) stop(d *data[E]) bool {
+	if d.timer.Stop() {
+		s.armed.Add(-1)
+		return true
+	}
+
+	return false
+}
+
 // DB is an interface that defines methods for storing and deleting events in a
 // persistent storage. It is used by the Persistent store to interact with the
 // underlying database or any other persistent storage mechanism.
@@ -73,45 +657,317 @@ var _ Store[any, Event] = &Persistent[any, Event]{}
 // Persistent implements the Store interface using both persistent storage (DB)
 // and in-memory storage.
 type Persistent[ID comparable, E Event] struct {
-	db DB[ID, E]
-	s  Simple[ID, E]
+	db      DB[ID, E]
+	s       Simple[ID, E]
+	opts    options
+	deletes *deferredDeletes[ID, E]
+	fired   atomic.Pointer[firedFilter[ID]]
+
+	onPromote atomic.Pointer[func(id ID, event E, err error)]
 }
 
 // NewPersistentStore creates a new Persistent store with the given DB.
 // It initializes the Persistent store with the provided DB for persistent
 // storage.
-func NewPersistentStore[ID comparable, E Event](db DB[ID, E]) *Persistent[ID, E] {
-	return &Persistent[ID, E]{db: db}
+func NewPersistentStore[ID comparable, E Event](db DB[ID, E], opts ...Option) *Persistent[ID, E] {
+	p := &Persistent[ID, E]{db: db, opts: newOptions(opts)}
+	p.s.init(p.opts)
+	p.s.onExpire = p.expired
+	p.s.onEvict = func(id ID, event E) { p.delete(id, event, ReasonCancelled) }
+	if p.opts.deleteInterval > 0 {
+		p.deletes = newDeferredDeletes(db, p.opts.deleteInterval, p.opts.deleteBatch)
+	}
+	return p
 }
 
 // Start stores the event in the persistent storage (db) and the in-memory
 // store (s). It first puts the event in the persistent storage using db.Put.
 // Then, it starts the event in the in-memory store using s.Start. When the
 // event expires, it deletes the event from the persistent storage and calls
-// atExpire.
+// atExpire. If atExpire is nil, the default handler is called instead (see
+// SetDefaultHandler).
+//
+// With WithLazyPersist, events due beyond the window are not put at once; see
+// WithLazyPersist.
 func (p *Persistent[ID, E]) Start(id ID, event E, atExpire func()) error {
-	if err := p.db.Put(id, event); err != nil {
+	if w := p.opts.lazyWindow; w > 0 && time.Until(event.ExpireAt()) > w {
+		return p.startLazy(id, event, atExpire)
+	}
+
+	if err := p.put(id, event); err != nil {
+		return kept(err)
+	}
+
+	return p.rollback(id, event, p.s.start(id, event, atExpire))
+}
+
+// put checks that the in-memory store admits the event before storing it in
+// the DB, so that an event rejected by the in-memory store is never persisted.
+func (p *Persistent[ID, E]) put(id ID, event E) error {
+	p.s.clamp(event, event.ExpireAt())
+	if err := p.s.check(id, event); err != nil {
 		return err
 	}
 
-	return p.s.Start(id, event, func() {
-		p.db.Delete(id, event)
-		atExpire()
-	})
+	return p.db.Put(id, event)
+}
+
+// rollback deletes an event from the DB again if err, returned when starting
+// it in memory after put, is not nil. This only happens if a concurrent Start
+// changed the outcome of the admission check done by put.
+func (p *Persistent[ID, E]) rollback(id ID, event E, err error) error {
+	if err != nil {
+		p.delete(id, event, ReasonCancelled)
+	}
+
+	return kept(err)
+}
+
+// SetDefaultHandler registers the handler called for events that have no
+// per-Start callback: events recovered from the DB by Recover, and events
+// started with a nil atExpire. A per-Start atExpire always takes precedence
+// and the default handler is not called for such events. The handler is looked
+// up when the event expires, so it may be set after Recover; an event that
+// expires while no handler is set is just removed. Passing nil removes the
+// handler. It is the same handler as Simple.SetExpiryHandler.
+func (p *Persistent[ID, E]) SetDefaultHandler(handler func(id ID, event E)) {
+	p.s.SetExpiryHandler(handler)
+}
+
+// SetExpiryHandler is SetDefaultHandler.
+func (p *Persistent[ID, E]) SetExpiryHandler(handler func(id ID, event E)) {
+	p.s.SetExpiryHandler(handler)
+}
+
+// expired deletes an expired event from the DB. It is the expiry hook of the
+// in-memory store.
+func (p *Persistent[ID, E]) expired(id ID, event E) {
+	if f := p.fired.Load(); f != nil {
+		f.add(id)
+	}
+
+	if p.deletes != nil {
+		p.deletes.add(id, event)
+	} else {
+		p.delete(id, event, ReasonExpired)
+	}
+}
+
+// StartReliable is like Start, but gives at-least-once delivery. When the event
+// expires it is removed from the in-memory store, but stays in the persistent
+// storage until the handler calls ack. If ack has not been called by the time
+// atExpire returns, the event is delivered again after a backoff delay (see
+// WithRedeliveryBackoff) until it is acknowledged. Because the event is only
+// deleted from the DB on ack, a crash while handling it leaves it in the DB to
+// be delivered again on recovery.
+//
+// ack may be called from any goroutine, at any time, and more than once.
+// Events awaiting acknowledgement are not counted by Len and cannot be
+// cancelled. While waiting for a redelivery, the event is stored again: it
+// counts in Len, and cancelling it ends the redelivery.
+func (p *Persistent[ID, E]) StartReliable(id ID, event E, atExpire func(ack func())) error {
+	if err := p.put(id, event); err != nil {
+		return kept(err)
+	}
+
+	r := &reliable[ID, E]{p: p, id: id, event: event, atExpire: atExpire}
+	return p.rollback(id, event, p.s.startUnhooked(id, event, r.deliver))
+}
+
+// reliable tracks the delivery state of an event started with
+// Persistent.StartReliable.
+type reliable[ID comparable, E Event] struct {
+	p        *Persistent[ID, E]
+	id       ID
+	event    E
+	atExpire func(ack func())
+
+	mu      sync.Mutex
+	acked   bool
+	attempt int
+	pending *data[E] // the entry waiting for the next redelivery
+}
+
+func (r *reliable[ID, E]) deliver() {
+	r.atExpire(r.ack)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.acked {
+		return
+	}
+
+	// The redelivery goes through the store, as for Nack, so that Close stops
+	// it and waits for its callback. An id started again meanwhile, a closed
+	// store or one at capacity end it; the event stays in the DB for a later
+	// Recover.
+	s := &r.p.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.m[r.id]; ok {
+		return
+	}
+	d, err := s.add(r.id, r.event, time.Now().Add(r.p.opts.redeliveryDelay(r.attempt)), r.deliver)
+	if err != nil {
+		return
+	}
+	d.unhook = true
+	r.pending = d
+	r.attempt++
+}
+
+func (r *reliable[ID, E]) ack() {
+	r.mu.Lock()
+	if r.acked {
+		r.mu.Unlock()
+		return
+	}
+	r.acked = true
+	if d := r.pending; d != nil {
+		s := &r.p.s
+		s.mu.Lock()
+		if s.m[r.id] == d {
+			s.cancel(r.id, d)
+		}
+		s.mu.Unlock()
+	}
+	r.mu.Unlock()
+
+	r.p.delete(r.id, r.event, ReasonExpired)
 }
 
 // Cancel stops the timer for the given id and removes the event from both the
 // in-memory store (s) ans the persistent storage (db). It first cancels the
 // event in the in-memory store using s.Cancel. If the event was successfully
 // cancelled in the in-memory store, it then deletes the event from the
-// persistent storage using db.Delete.
+// persistent storage using db.Delete. With WithReadThrough, an event not in
+// memory is looked up in the DB and deleted from it if found.
 func (p *Persistent[ID, E]) Cancel(id ID) (E, bool) {
 	event, ok := p.s.Cancel(id)
+	if !ok && p.opts.readThrough {
+		// Not in memory: delete it from the DB without arming it.
+		event, ok, _ = p.lookup(id)
+	}
 	if !ok {
 		var zeroE E
 		return zeroE, false
 	}
 
-	p.db.Delete(id, event)
+	p.delete(id, event, ReasonCancelled)
 	return event, true
 }
+
+// CancelChecked is like Cancel, but also returns the error of deleting the
+// event from the DB, so that the caller knows whether a stale event may be
+// left behind in it, to be recovered by the next Recover. The timer is
+// stopped whether or not the deletion fails. Only a DB implementing
+// CheckedDeleter can report an error; with another DB, the error is always
+// nil. It reports the event absent, deleting nothing, if id is not stored in
+// memory.
+func (p *Persistent[ID, E]) CancelChecked(id ID) (E, bool, error) {
+	event, ok := p.s.Cancel(id)
+	if !ok {
+		return event, false, nil
+	}
+
+	if cd, isChecked := p.db.(CheckedDeleter[ID, E]); isChecked {
+		return event, true, cd.DeleteChecked(id, event, ReasonCancelled)
+	}
+
+	p.delete(id, event, ReasonCancelled)
+	return event, true, nil
+}
+
+// CancelWithHandler is like Cancel, but also returns the callback the event
+// was started with. See Simple.CancelWithHandler.
+func (p *Persistent[ID, E]) CancelWithHandler(id ID) (E, func(), bool) {
+	event, atExpire, ok := p.s.CancelWithHandler(id)
+	if ok {
+		p.delete(id, event, ReasonCancelled)
+	}
+
+	return event, atExpire, ok
+}
+
+// ExpireWhere expires every event stored in memory for which match returns
+// true right away, deleting them from the DB as they fire. See
+// Simple.ExpireWhere.
+func (p *Persistent[ID, E]) ExpireWhere(match func(id ID, event E) bool) int {
+	return p.s.ExpireWhere(match)
+}
+
+// CancelKeepPersisted stops the timer for id and removes the event from
+// memory like Cancel, but leaves it in the DB. It returns the event and
+// whether it was present.
+//
+// Afterwards the DB holds an event the store no longer tracks: it will not
+// expire, and nothing deletes it. The caller owns the record from then on and
+// must either start the event again, which puts it anew, or delete it from
+// the DB itself. A later Recover re-arms it along with everything else the DB
+// lists.
+func (p *Persistent[ID, E]) CancelKeepPersisted(id ID) (E, bool) {
+	return p.s.Cancel(id)
+}
+
+// CancelAll stops every timer, removes every event from memory and deletes
+// them from the DB, and returns the number of events cancelled.
+func (p *Persistent[ID, E]) CancelAll() int { return len(p.cancelWhere(nil)) }
+
+// CancelMatching cancels every event whose id match returns true for, in
+// memory and in the DB, and returns the cancelled events. See
+// Simple.CancelMatching.
+func (p *Persistent[ID, E]) CancelMatching(match func(id ID) bool) []E {
+	return eventsOf(p.cancelWhere(func(id ID, _ *data[E]) bool { return match(id) }))
+}
+
+// CancelExpiringBetween cancels every event stored in memory due in
+// [from, to), in memory and in the DB, and returns the cancelled events. See
+// Simple.CancelExpiringBetween.
+func (p *Persistent[ID, E]) CancelExpiringBetween(from, to time.Time) []E {
+	return eventsOf(p.cancelWhere(between[ID, E](from, to)))
+}
+
+func (p *Persistent[ID, E]) cancelWhere(match func(id ID, d *data[E]) bool) []Entry[ID, E] {
+	entries := p.s.cancelWhere(match)
+	for _, e := range entries {
+		p.delete(e.ID, e.Event, ReasonCancelled)
+	}
+
+	return entries
+}
+
+// delete removes an event from the DB, passing reason along if the DB
+// implements ReasonDeleter.
+func (p *Persistent[ID, E]) delete(id ID, event E, reason Reason) {
+	deleteFrom(p.db, id, event, reason)
+}
+
+// CurrentWeight returns the total weight of the events stored in memory. See
+// Simple.CurrentWeight.
+func (p *Persistent[ID, E]) CurrentWeight() int64 { return p.s.CurrentWeight() }
+
+// Get returns the event stored in memory for id and whether it is present.
+// With WithReadThrough, an event not in memory is loaded from the DB as by
+// Load, and reported absent if that fails.
+func (p *Persistent[ID, E]) Get(id ID) (E, bool) {
+	if p.opts.readThrough {
+		if _, ok := p.s.peek(id); !ok {
+			event, ok, _ := p.Load(id)
+			return event, ok
+		}
+	}
+
+	return p.s.Get(id)
+}
+
+// Len returns the number of events currently stored in memory.
+func (p *Persistent[ID, E]) Len() int { return p.s.Len() }
+
+// ActiveTimers returns the number of timers currently armed by the in-memory
+// store. See Simple.ActiveTimers.
+func (p *Persistent[ID, E]) ActiveTimers() int { return p.s.ActiveTimers() }
+
+// DelayedExpiries returns the number of expired events whose callbacks are
+// held back by the expiry rate limit. See Simple.DelayedExpiries.
+func (p *Persistent[ID, E]) DelayedExpiries() int { return p.s.DelayedExpiries() }
@@ -0,0 +1,213 @@
+package timerstore
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testEvent is the event type of the tests. Its fields are exported for the
+// codecs.
+type testEvent struct {
+	At   time.Time
+	Name string
+}
+
+func (e testEvent) ExpireAt() time.Time { return e.At }
+
+// mapDB is an in-memory DB for testing Persistent.
+type mapDB[E Event] struct {
+	mu sync.Mutex
+	m  map[string]E
+}
+
+func newMapDB[E Event]() *mapDB[E] { return &mapDB[E]{m: make(map[string]E)} }
+
+func (db *mapDB[E]) Put(id string, event E) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.m[id] = event
+	return nil
+}
+
+func (db *mapDB[E]) Delete(id string, _ E) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	delete(db.m, id)
+}
+
+func (db *mapDB[E]) Get(id string) (E, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	event, ok := db.m[id]
+	if !ok {
+		var zeroE E
+		return zeroE, ErrNotFound
+	}
+	return event, nil
+}
+
+func (db *mapDB[E]) List() ([]Entry[string, E], error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	entries := make([]Entry[string, E], 0, len(db.m))
+	for id, event := range db.m {
+		entries = append(entries, Entry[string, E]{ID: id, Event: event})
+	}
+	return entries, nil
+}
+
+func (db *mapDB[E]) has(id string) bool {
+	_, err := db.Get(id)
+	return err == nil
+}
+
+func TestStartReliableCloseStopsRedelivery(t *testing.T) {
+	db := newMapDB[testEvent]()
+	p := NewPersistentStore[string, testEvent](db, WithRedeliveryBackoff(time.Millisecond, time.Millisecond))
+
+	var calls atomic.Int32
+	delivered := make(chan struct{}, 1)
+	err := p.StartReliable("a", testEvent{At: time.Now()}, func(ack func()) {
+		calls.Add(1)
+		select {
+		case delivered <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		t.Fatalf("StartReliable: %v", err)
+	}
+
+	<-delivered
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	n := calls.Load()
+	time.Sleep(20 * time.Millisecond)
+	if got := calls.Load(); got != n {
+		t.Errorf("atExpire called %d times after Close; want none", got-n)
+	}
+	if !db.has("a") {
+		t.Error("unacknowledged event deleted from the DB")
+	}
+}
+
+func TestStartReliableAck(t *testing.T) {
+	db := newMapDB[testEvent]()
+	p := NewPersistentStore[string, testEvent](db, WithRedeliveryBackoff(time.Millisecond, time.Millisecond))
+	defer p.Close()
+
+	var calls atomic.Int32
+	done := make(chan struct{})
+	err := p.StartReliable("a", testEvent{At: time.Now()}, func(ack func()) {
+		if calls.Add(1) == 3 {
+			ack()
+			close(done)
+		}
+	})
+	if err != nil {
+		t.Fatalf("StartReliable: %v", err)
+	}
+
+	<-done
+	time.Sleep(20 * time.Millisecond)
+	if got := calls.Load(); got != 3 {
+		t.Errorf("atExpire called %d times; want 3", got)
+	}
+	if db.has("a") || p.Len() != 0 {
+		t.Error("acknowledged event still stored")
+	}
+}
+
+func TestStartCancelNoGoroutineLeak(t *testing.T) {
+	s := NewSimple[string, testEvent]()
+	defer s.Close()
+
+	before := runtime.NumGoroutine()
+	at := time.Now().Add(time.Hour)
+	for i := range 500 {
+		id := fmt.Sprint("cancel-", i)
+		if err := s.Start(id, testEvent{At: at}, func() {}); err != nil {
+			t.Fatalf("Start(%q): %v", id, err)
+		}
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("%d goroutines with 500 timers armed, %d before", n, before)
+	}
+
+	var fired sync.WaitGroup
+	for i := range 500 {
+		id := fmt.Sprint("expire-", i)
+		fired.Add(1)
+		if err := s.Start(id, testEvent{At: time.Now()}, fired.Done); err != nil {
+			t.Fatalf("Start(%q): %v", id, err)
+		}
+	}
+	for i := range 500 {
+		s.Cancel(fmt.Sprint("cancel-", i))
+	}
+	fired.Wait()
+
+	// The goroutines that ran the callbacks may take a moment to exit.
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("%d goroutines after Start/Cancel, %d before", n, before)
+	}
+	if n := s.ActiveTimers(); n != 0 {
+		t.Errorf("ActiveTimers = %d; want 0", n)
+	}
+}
+
+func TestStartFromAtExpire(t *testing.T) {
+	db := newMapDB[testEvent]()
+	p := NewPersistentStore[string, testEvent](db)
+	defer p.Close()
+
+	const rearms = 100
+	done := make(chan struct{})
+	var n int
+	var atExpire func()
+	atExpire = func() {
+		n++
+		if n == rearms {
+			close(done)
+			return
+		}
+		if err := p.Start("a", testEvent{At: time.Now()}, atExpire); err != nil {
+			t.Errorf("Start from atExpire: %v", err)
+		}
+		if _, ok := p.Get("a"); !ok {
+			t.Error("event re-armed from atExpire not stored")
+		}
+	}
+	if err := p.Start("a", testEvent{At: time.Now()}, atExpire); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("re-armed event did not fire %d times", rearms)
+	}
+
+	// The last expiry did not re-arm, so nothing may be left behind.
+	time.Sleep(10 * time.Millisecond)
+	if p.Len() != 0 || db.has("a") {
+		t.Error("event still stored after its last expiry")
+	}
+	if err := p.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package timerstore
+
+import "time"
+
+// Suspend freezes the countdown of the event stored for id: its timer is
+// stopped and the time left until its deadline is recorded. A suspended event
+// stays in the store, so Get and Cancel work as usual, but it does not expire
+// until Unsuspend. Moving its deadline, such as with Reschedule, updates the
+// recorded time left without arming the timer. Suspend reports whether id
+// was present and not already suspended.
+func (s *Simple[ID, E]) Suspend(id ID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.m[id]
+	if !ok || d.suspended {
+		return false
+	}
+
+	// If the timer already fired, expire sees the flag and leaves d alone.
+	s.stop(d)
+	d.suspended, d.remaining = true, d.deadline.Sub(time.Now())
+	return true
+}
+
+// Unsuspend resumes the countdown of the event stored for id, which must have
+// been suspended with Suspend: its deadline becomes the recorded time left
+// from now, and its timer is armed again. If the event implements
+// ExpirySetter, its ExpireAt is updated too. Unsuspend reports whether id was
+// present and suspended.
+func (s *Simple[ID, E]) Unsuspend(id ID) bool {
+	s.mu.Lock()
+	d, ok := s.m[id]
+	if !ok || !d.suspended {
+		s.mu.Unlock()
+		return false
+	}
+
+	now := time.Now()
+	old, at := d.deadline, now.Add(d.remaining)
+	d.suspended, d.remaining, d.deadline = false, 0, at
+	if setter, ok := any(d.event).(ExpirySetter); ok {
+		setter.SetExpireAt(at)
+	}
+	s.repromote(d)
+	s.reset(d, now, at)
+	s.mu.Unlock()
+
+	s.rescheduled(id, old, at)
+	return true
+}
+
+// IsSuspended reports whether the event stored for id is suspended.
+func (s *Simple[ID, E]) IsSuspended(id ID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.m[id]
+	return ok && d.suspended
+}
+
+// Suspend freezes the countdown of the event stored in memory for id. The DB
+// is not updated, so a Recover after a restart re-arms the event for the
+// ExpireAt it was stored with. See Simple.Suspend.
+func (p *Persistent[ID, E]) Suspend(id ID) bool { return p.s.Suspend(id) }
+
+// Unsuspend resumes the countdown of the event stored in memory for id. See
+// Simple.Unsuspend.
+func (p *Persistent[ID, E]) Unsuspend(id ID) bool { return p.s.Unsuspend(id) }
+
+// IsSuspended reports whether the event stored in memory for id is suspended.
+func (p *Persistent[ID, E]) IsSuspended(id ID) bool { return p.s.IsSuspended(id) }
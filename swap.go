@@ -0,0 +1,77 @@
+package timerstore
+
+// Swap cancels the event stored for oldID and starts event under newID in one
+// step: no other operation on the store observes a state where both or neither
+// are stored. It returns the cancelled event and whether oldID was present. If
+// the store does not admit the new event, Swap fails with the error Start would
+// return and leaves oldID stored; the capacity check counts oldID as already
+// gone, and never evicts it to make room for the new event. Events evicted
+// before such a failure stay evicted, as with Start. If the replace mode keeps
+// an event already stored under newID, Swap changes nothing and reports oldID
+// absent. With oldID equal to newID, Swap is Start returning the replaced
+// event.
+func (s *Simple[ID, E]) Swap(oldID, newID ID, event E, atExpire func()) (E, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var zeroE E
+	d, ok := s.m[oldID]
+	if ok && oldID != newID {
+		// Make room for the new event as if oldID were already gone, but
+		// before cancelling it, so that oldID stays stored if Start would
+		// fail. oldID is out of the map meanwhile, not to be evicted.
+		delete(s.m, oldID)
+		s.weight -= d.weight
+		if d.pinned {
+			s.pinned--
+		}
+		deadline := s.clamp(event, event.ExpireAt())
+		err := s.admit(newID, event, deadline)
+		if err == errEvict {
+			err = s.evict(newID, event, deadline)
+		}
+		s.m[oldID] = d
+		s.weight += d.weight
+		if d.pinned {
+			s.pinned++
+		}
+		if err != nil {
+			return zeroE, false, kept(err)
+		}
+
+		s.cancel(oldID, d)
+	}
+
+	if _, err := s.add(newID, event, event.ExpireAt(), atExpire); err != nil {
+		return zeroE, false, kept(err)
+	}
+
+	if !ok {
+		return zeroE, false, nil
+	}
+
+	return d.event, true, nil
+}
+
+// Swap cancels the event stored for oldID and starts event under newID, as
+// Simple.Swap does in memory. In the DB the new event is put first and the old
+// one deleted after the swap, so a crash in between leaves both persisted, to
+// be recovered together, but never neither. If the put fails, nothing
+// changes.
+func (p *Persistent[ID, E]) Swap(oldID, newID ID, event E, atExpire func()) (E, bool, error) {
+	var zeroE E
+	if err := p.put(newID, event); err != nil {
+		return zeroE, false, kept(err)
+	}
+
+	prev, ok, err := p.s.Swap(oldID, newID, event, atExpire)
+	if err != nil {
+		return zeroE, false, p.rollback(newID, event, err)
+	}
+
+	if ok && oldID != newID {
+		p.delete(oldID, prev, ReasonCancelled)
+	}
+
+	return prev, ok, nil
+}
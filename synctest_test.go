@@ -0,0 +1,36 @@
+//go:build go1.25
+
+package timerstore_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/chanchal1987/timerstore"
+)
+
+type event struct{ at time.Time }
+
+func (e event) ExpireAt() time.Time { return e.at }
+
+// TestSynctest runs the example of the package documentation.
+func TestSynctest(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s := timerstore.NewSimple[string, event]()
+		defer s.Close()
+
+		var fired atomic.Bool
+		start := time.Now()
+		s.Start("a", event{at: time.Now().Add(time.Hour)}, func() { fired.Store(true) })
+		time.Sleep(time.Hour) // returns at once
+		synctest.Wait()       // until the callback has run
+		if !fired.Load() {
+			t.Error("event did not fire")
+		}
+		if elapsed := time.Since(start); elapsed != time.Hour {
+			t.Errorf("fake clock advanced by %v; want 1h", elapsed)
+		}
+	})
+}
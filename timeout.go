@@ -0,0 +1,53 @@
+package timerstore
+
+import "time"
+
+// OnCallbackTimeout registers fn to be called with the id of every event
+// whose expiry callback is still running after the timeout set with
+// WithCallbackTimeout. fn runs on a goroutine of its own while the callback
+// may still be running. Passing nil removes the hook.
+func (s *Simple[ID, E]) OnCallbackTimeout(fn func(id ID)) {
+	if fn == nil {
+		s.onTimeout.Store(nil)
+		return
+	}
+
+	s.onTimeout.Store(&fn)
+}
+
+// timed runs fn, reporting it to the timeout hook if it runs longer than t.
+// In DispatchPool mode, fn runs on a goroutine of its own and timed returns
+// when t is over even if fn has not returned.
+func (s *Simple[ID, E]) timed(id ID, t time.Duration, fn func()) {
+	if s.pool == nil {
+		timer := time.AfterFunc(t, func() { s.timedOut(id) })
+		defer timer.Stop()
+
+		fn()
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+
+	timer := time.NewTimer(t)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		go s.timedOut(id)
+	}
+}
+
+func (s *Simple[ID, E]) timedOut(id ID) {
+	if h := s.onTimeout.Load(); h != nil {
+		(*h)(id)
+	}
+}
+
+// OnCallbackTimeout registers fn to be called with the id of every event
+// whose expiry callback overruns its timeout. See Simple.OnCallbackTimeout.
+func (p *Persistent[ID, E]) OnCallbackTimeout(fn func(id ID)) { p.s.OnCallbackTimeout(fn) }
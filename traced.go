@@ -0,0 +1,43 @@
+package timerstore
+
+import "context"
+
+// StartTraced is like Start, but carries ctx over to the expiry: atExpire is
+// called with a context holding the values of ctx, such as the span of a
+// tracer, so it can start a child span linked to the one that scheduled the
+// event. The context passed to atExpire is detached from the cancellation
+// and deadline of ctx, which have usually ended long before the event
+// expires. The package knows nothing of any tracer; it only propagates ctx.
+//
+// The store keeps ctx until the event leaves it, and with it everything ctx
+// references. For events scheduled far ahead, derive ctx from one holding
+// only the propagation values needed rather than from a whole request
+// context.
+//
+// With WithCallbackTimeout, the context passed to atExpire has a deadline of
+// the timeout from when atExpire is called.
+func (s *Simple[ID, E]) StartTraced(ctx context.Context, id ID, event E, atExpire func(ctx context.Context)) error {
+	return s.Start(id, event, s.traced(ctx, atExpire))
+}
+
+// traced binds atExpire to the values of ctx.
+func (s *Simple[ID, E]) traced(ctx context.Context, atExpire func(ctx context.Context)) func() {
+	ctx = context.WithoutCancel(ctx)
+	return func() {
+		if t := s.opts.callbackTimeout; t > 0 {
+			ctx, cancel := context.WithTimeout(ctx, t)
+			defer cancel()
+			atExpire(ctx)
+			return
+		}
+
+		atExpire(ctx)
+	}
+}
+
+// StartTraced is like Start, but carries ctx over to the expiry. Events
+// recovered from the DB have lost their context and go to the default
+// handler. See Simple.StartTraced.
+func (p *Persistent[ID, E]) StartTraced(ctx context.Context, id ID, event E, atExpire func(ctx context.Context)) error {
+	return p.Start(id, event, p.s.traced(ctx, atExpire))
+}
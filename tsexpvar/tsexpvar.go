@@ -0,0 +1,36 @@
+// Package tsexpvar publishes the counters of a timerstore as an expvar, for
+// monitoring through /debug/vars with nothing but the standard library.
+package tsexpvar
+
+import (
+	"expvar"
+
+	"github.com/chanchal1987/timerstore"
+)
+
+// counters is the value published for a store.
+type counters struct {
+	Live          int    `json:"live"`
+	Started       uint64 `json:"started"`
+	Cancelled     uint64 `json:"cancelled"`
+	Expired       uint64 `json:"expired"`
+	HighWaterMark int    `json:"highWaterMark"`
+}
+
+// PublishExpvar publishes the counters of s under name: the number of live
+// events, the numbers of events started, cancelled and expired, and the
+// high-water mark. They are read from s every time the variable is, as with
+// Simple.Stats, so they are not a consistent snapshot while s is in use. Like
+// expvar.Publish, it panics if name is already registered.
+func PublishExpvar[ID comparable, E timerstore.Event](name string, s *timerstore.Simple[ID, E]) {
+	expvar.Publish(name, expvar.Func(func() any {
+		st := s.Stats()
+		return counters{
+			Live:          st.Len,
+			Started:       st.Started,
+			Cancelled:     st.Cancelled,
+			Expired:       st.Expired,
+			HighWaterMark: st.HighWaterMark,
+		}
+	}))
+}
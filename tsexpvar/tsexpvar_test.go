@@ -0,0 +1,46 @@
+package tsexpvar
+
+import (
+	"encoding/json"
+	"expvar"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chanchal1987/timerstore"
+)
+
+type event time.Time
+
+func (e event) ExpireAt() time.Time { return time.Time(e) }
+
+// runs makes the published names unique across runs of the test with -count,
+// since a name cannot be published twice.
+var runs atomic.Int32
+
+func TestPublishExpvar(t *testing.T) {
+	s := timerstore.NewSimple[string, event]()
+	defer s.Close()
+
+	name := "tsexpvar.TestPublishExpvar." + strconv.Itoa(int(runs.Add(1)))
+	PublishExpvar(name, s)
+
+	at := event(time.Now().Add(time.Hour))
+	s.Start("a", at, func() {})
+	s.Start("b", at, func() {})
+	s.Cancel("b")
+
+	v := expvar.Get(name)
+	if v == nil {
+		t.Fatalf("%s not published", name)
+	}
+	var got counters
+	if err := json.Unmarshal([]byte(v.String()), &got); err != nil {
+		t.Fatalf("unmarshal %s: %v", v.String(), err)
+	}
+	want := counters{Live: 1, Started: 2, Cancelled: 1, HighWaterMark: 2}
+	if got != want {
+		t.Errorf("published %+v; want %+v", got, want)
+	}
+}
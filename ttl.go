@@ -0,0 +1,23 @@
+package timerstore
+
+import "time"
+
+// WithDefaultTTL wraps s so that every event gets the same fixed TTL. Start on
+// the returned store treats its event as a payload: it calls wrap with the
+// payload and a deadline of ttl from now, and starts the resulting event in s.
+// The ExpireAt of the payload is ignored. wrap must return an event whose
+// ExpireAt is the given expiry, since that is what s arms the timer for.
+// Cancel is forwarded to s unchanged.
+func WithDefaultTTL[ID comparable, E Event](s Store[ID, E], ttl time.Duration, wrap func(payload E, expiry time.Time) E) Store[ID, E] {
+	return &defaultTTL[ID, E]{Store: s, ttl: ttl, wrap: wrap}
+}
+
+type defaultTTL[ID comparable, E Event] struct {
+	Store[ID, E]
+	ttl  time.Duration
+	wrap func(payload E, expiry time.Time) E
+}
+
+func (t *defaultTTL[ID, E]) Start(id ID, payload E, atExpire func()) error {
+	return t.Store.Start(id, t.wrap(payload, time.Now().Add(t.ttl)), atExpire)
+}
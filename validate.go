@@ -0,0 +1,76 @@
+package timerstore
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Validate checks the internal invariants of the store and returns an error
+// describing every violation found, or nil. It is meant to be called from
+// tests after a sequence of operations, not from production code: it holds
+// the store lock for O(n).
+//
+// Validate expects a quiescent store. An event whose timer has fired but whose
+// expiry has not yet taken the lock is reported as fired but not removed, so
+// call it only when no stored event is due.
+func (s *Simple[ID, E]) Validate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	var weight, n int64
+	for id, d := range s.m {
+		if !d.suspended {
+			n++
+		}
+		if d.timer == nil {
+			errs = append(errs, fmt.Errorf("timerstore: event %v has no timer", id))
+		}
+		weight += d.weight
+	}
+
+	if weight != s.weight {
+		errs = append(errs, fmt.Errorf("timerstore: weight is %d, events weigh %d", s.weight, weight))
+	}
+
+	if armed := s.armed.Load(); armed > n {
+		errs = append(errs, fmt.Errorf("timerstore: %d timers armed for %d running events, stopped timers still counted", armed, n))
+	} else if armed < n {
+		errs = append(errs, fmt.Errorf("timerstore: %d timers armed for %d running events, fired events not removed", armed, n))
+	}
+
+	if peak := s.peak.Load(); peak < int64(len(s.m)) {
+		errs = append(errs, fmt.Errorf("timerstore: high-water mark %d below %d events", peak, len(s.m)))
+	}
+
+	if done := s.cancelled.Load() + s.expired.Load() + uint64(len(s.m)); done > s.started.Load() {
+		errs = append(errs, fmt.Errorf("timerstore: %d events started, %d accounted for", s.started.Load(), done))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate checks the invariants of the in-memory store, see Simple.Validate,
+// and, if the DB is a Getter, that every event held in memory is stored in the
+// DB too, except events not yet put by WithLazyPersist.
+func (p *Persistent[ID, E]) Validate() error {
+	errs := []error{p.s.Validate()}
+	if g, ok := p.db.(Getter[ID, E]); ok {
+		p.s.mu.Lock()
+		ids := make([]ID, 0, len(p.s.m))
+		for id, d := range p.s.m {
+			if d.promote == nil {
+				ids = append(ids, id)
+			}
+		}
+		p.s.mu.Unlock()
+
+		for _, id := range ids {
+			if _, err := g.Get(id); err != nil {
+				errs = append(errs, fmt.Errorf("timerstore: event %v in memory but not in DB: %w", id, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,163 @@
+package timerstore
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// ReadOnlyView is a point-in-time copy of the events held by a store, taken by
+// Freeze. It does not reflect any Start, Cancel or expiry that happens after
+// Freeze returns. A view never changes, so it may be read from any number of
+// goroutines without locking.
+type ReadOnlyView[ID comparable, E Event] struct {
+	m map[ID]E
+}
+
+// Get returns the event stored for id when the view was taken.
+func (v ReadOnlyView[ID, E]) Get(id ID) (E, bool) {
+	event, ok := v.m[id]
+	return event, ok
+}
+
+// Len returns the number of events in the view.
+func (v ReadOnlyView[ID, E]) Len() int { return len(v.m) }
+
+// Range calls fn for every event in the view, in no particular order, until fn
+// returns false.
+func (v ReadOnlyView[ID, E]) Range(fn func(id ID, event E) bool) {
+	for id, event := range v.m {
+		if !fn(id, event) {
+			return
+		}
+	}
+}
+
+// Count returns the number of events in the view for which match returns true.
+func (v ReadOnlyView[ID, E]) Count(match func(id ID, event E) bool) int {
+	n := 0
+	for id, event := range v.m {
+		if match(id, event) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Freeze returns a read-only copy of the events currently stored. Taking the
+// copy holds the store lock for O(n); reads on the returned view take no lock
+// at all.
+func (s *Simple[ID, E]) Freeze() ReadOnlyView[ID, E] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := make(map[ID]E, len(s.m))
+	for id, d := range s.m {
+		m[id] = d.event
+	}
+
+	return ReadOnlyView[ID, E]{m: m}
+}
+
+// Freeze returns a read-only copy of the events currently stored in memory.
+// See Simple.Freeze.
+func (p *Persistent[ID, E]) Freeze() ReadOnlyView[ID, E] { return p.s.Freeze() }
+
+// Keys returns the ids of all events currently stored, in no particular
+// order. The result is a point-in-time snapshot and may be stale the instant
+// it returns.
+func (s *Simple[ID, E]) Keys() []ID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]ID, 0, len(s.m))
+	for id := range s.m {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// RangeKeys calls fn for the id of every event currently stored, in no
+// particular order, until fn returns false. Unlike Keys it allocates nothing,
+// but it holds the store lock while iterating: fn must be quick and must not
+// call any method of the store.
+func (s *Simple[ID, E]) RangeKeys(fn func(id ID) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id := range s.m {
+		if !fn(id) {
+			return
+		}
+	}
+}
+
+// RangeByInsertion calls fn for every event currently stored, in the order
+// they were started, until fn returns false. An event replaced by a later
+// Start takes the place of that Start; moving its deadline does not change
+// its place. It reads the store once, then calls fn with the store lock
+// released, so fn may call the store but does not see changes made after
+// RangeByInsertion was called. Simple keeps no insertion order, so it copies
+// and sorts the stored events, costing O(n log n) in their number.
+func (s *Simple[ID, E]) RangeByInsertion(fn func(id ID, event E) bool) {
+	type record struct {
+		seq   uint64
+		id    ID
+		event E
+	}
+
+	s.mu.Lock()
+	records := make([]record, 0, len(s.m))
+	for id, d := range s.m {
+		records = append(records, record{d.seq, id, d.event})
+	}
+	s.mu.Unlock()
+
+	slices.SortFunc(records, func(a, b record) int { return cmp.Compare(a.seq, b.seq) })
+	for _, r := range records {
+		if !fn(r.id, r.event) {
+			return
+		}
+	}
+}
+
+// RangeByInsertion calls fn for every event stored in memory, in the order
+// they were started. See Simple.RangeByInsertion.
+func (p *Persistent[ID, E]) RangeByInsertion(fn func(id ID, event E) bool) { p.s.RangeByInsertion(fn) }
+
+// Keys returns the ids of all events stored in memory. See Simple.Keys.
+func (p *Persistent[ID, E]) Keys() []ID { return p.s.Keys() }
+
+// RangeKeys calls fn for the id of every event stored in memory. See
+// Simple.RangeKeys.
+func (p *Persistent[ID, E]) RangeKeys(fn func(id ID) bool) { p.s.RangeKeys(fn) }
+
+// ExpiredAsOf returns the events that would have expired by t, those due at or
+// before t, in deadline order, without firing or removing them. t may be any
+// time, in the past or the future, which makes it suited to what-if analysis
+// and to tests. Simple keeps no ordering, so ExpiredAsOf scans every stored
+// event, holding the store lock.
+func (s *Simple[ID, E]) ExpiredAsOf(t time.Time) []Entry[ID, E] {
+	s.mu.Lock()
+	var due []entry[ID, E]
+	for id, d := range s.m {
+		if !d.deadline.After(t) {
+			due = append(due, entry[ID, E]{id, d})
+		}
+	}
+	slices.SortFunc(due, func(a, b entry[ID, E]) int { return a.d.deadline.Compare(b.d.deadline) })
+
+	entries := make([]Entry[ID, E], len(due))
+	for i, e := range due {
+		entries[i] = Entry[ID, E]{ID: e.id, Event: e.d.event}
+	}
+	s.mu.Unlock()
+
+	return entries
+}
+
+// ExpiredAsOf returns the events stored in memory that would have expired by
+// t. See Simple.ExpiredAsOf.
+func (p *Persistent[ID, E]) ExpiredAsOf(t time.Time) []Entry[ID, E] { return p.s.ExpiredAsOf(t) }